@@ -0,0 +1,291 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	logf "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/spool"
+)
+
+// newExecutions returns executions of the test without logs. Execution ID is equal to the name of the testing pod.
+func newExecutions(result octopusTypes.TestResult) []report.Execution {
+	decisive := decisiveExecution(result)
+
+	executions := make([]report.Execution, 0, len(result.Executions))
+	for i, exec := range result.Executions {
+		executions = append(executions, report.Execution{
+			ID:       exec.ID,
+			Attempt:  i + 1,
+			PodPhase: string(exec.PodPhase),
+			Reason:   exec.Reason,
+			Message:  exec.Message,
+			Decisive: i == decisive,
+		})
+	}
+	return executions
+}
+
+// newSuite describes the suite with its conditions and every test, regardless of how they're routed.
+func newSuite(cts octopusTypes.ClusterTestSuite) report.Suite {
+	suite := report.Suite{
+		Name:           cts.Name,
+		CompletionTime: cts.Status.CompletionTime.String(),
+	}
+	if cts.Status.StartTime != nil {
+		suite.StartTime = cts.Status.StartTime.Time
+	}
+
+	for _, cond := range cts.Status.Conditions {
+		suite.Conditions = append(suite.Conditions, report.Condition{
+			Type:    string(cond.Type),
+			Status:  string(cond.Status),
+			Reason:  cond.Reason,
+			Message: cond.Message,
+		})
+	}
+
+	for _, result := range cts.Status.Results {
+		summary := report.TestSummary{
+			Name:      result.Name,
+			Namespace: result.Namespace,
+			Status:    string(result.Status),
+			Duration:  testDuration(result),
+		}
+		if len(result.Executions) > 1 {
+			summary.Retries = len(result.Executions) - 1
+		}
+		suite.Tests = append(suite.Tests, summary)
+	}
+	return suite
+}
+
+// testDuration returns the time from the start of the first execution to the end of the last one, or zero if any of them is unknown.
+func testDuration(result octopusTypes.TestResult) time.Duration {
+	var start, end *metav1.Time
+	for _, exec := range result.Executions {
+		if exec.StartTime != nil && (start == nil || exec.StartTime.Before(start)) {
+			start = exec.StartTime
+		}
+		if exec.CompletionTime != nil && (end == nil || end.Before(exec.CompletionTime)) {
+			end = exec.CompletionTime
+		}
+	}
+
+	if start == nil || end == nil {
+		return 0
+	}
+	return end.Sub(start.Time)
+}
+
+// collectLogs fetches logs of every execution of given tests, except the ones reported with summary only, at most r.parallelism pods at once.
+// Logs are stored in place, so the order of results doesn't depend on the order in which pods are processed.
+// Failures don't stop the collection, they're returned as problems in the report order instead.
+func (r reporter) collectLogs(sp *spool.Spool, results []report.TestResult) []report.Problem {
+	type piece struct {
+		result, execution int
+	}
+
+	var pieces []piece
+	for i := range results {
+		if !results[i].NeedsLogs() {
+			continue
+		}
+		for j := range results[i].Executions {
+			pieces = append(pieces, piece{result: i, execution: j})
+		}
+	}
+
+	problems := make([][]report.Problem, len(pieces))
+	workqueue.ParallelizeUntil(context.Background(), r.parallelism, len(pieces), func(i int) {
+		result := &results[pieces[i].result]
+		exec := &result.Executions[pieces[i].execution]
+
+		exec.Containers, problems[i] = r.collectExecution(sp, *result, exec.ID)
+	})
+
+	var all []report.Problem
+	for _, p := range problems {
+		all = append(all, p...)
+	}
+	return all
+}
+
+// collectExecution fetches logs of selected containers of the testing pod within r.podTimeout.
+// Logs of containers which could be read are returned even if other containers failed.
+func (r reporter) collectExecution(sp *spool.Spool, result report.TestResult, podName string) ([]report.ContainerLog, []report.Problem) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.podTimeout)
+	defer cancel()
+
+	newProblem := func(container string, err error) report.Problem {
+		return report.Problem{
+			Test:      result.Name,
+			Execution: podName,
+			Container: container,
+			Message:   err.Error(),
+			Routes:    result.Routes,
+		}
+	}
+
+	pod, err := r.clientset.CoreV1().Pods(result.Namespace).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, []report.Problem{newProblem("", errors.Wrapf(err, "while getting pod %s in namespace %s", podName, result.Namespace))}
+	}
+
+	containers, err := r.containers.selectContainers(*pod)
+	if err != nil {
+		return nil, []report.Problem{newProblem("", errors.Wrapf(err, "while selecting containers of pod %s in namespace %s", pod.Name, pod.Namespace))}
+	}
+
+	var logs []report.ContainerLog
+	var problems []report.Problem
+	for _, container := range containers {
+		containerLogs, errs := r.collectContainer(ctx, sp, *pod, container)
+		for _, err := range errs {
+			problems = append(problems, newProblem(container.name, err))
+		}
+		logs = append(logs, containerLogs...)
+	}
+
+	return logs, problems
+}
+
+// collectContainer fetches logs of the container. If the container has been restarted,
+// logs of its previous instance are fetched as well and returned first. Logs which could be fetched
+// are returned even if the others failed, e.g. because logs of the previous instance have been already removed,
+// and so are logs whose stream has been cut short.
+func (r reporter) collectContainer(ctx context.Context, sp *spool.Spool, pod corev1.Pod, container selectedContainer) ([]report.ContainerLog, []error) {
+	status := findContainerStatus(pod, container)
+
+	var logs []report.ContainerLog
+	var errs []error
+	if status != nil && status.RestartCount > 0 {
+		log, err := r.fetchLogs(ctx, sp, pod, container.name, true)
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, "while fetching logs of the previous instance"))
+		}
+		if log.Path != "" {
+			logs = append(logs, report.ContainerLog{
+				Name:         container.name,
+				Init:         container.init,
+				Primary:      container.primary,
+				Previous:     true,
+				RestartCount: status.RestartCount,
+				Termination:  newTermination(status.LastTerminationState.Terminated),
+				Log:          log,
+			})
+		}
+	}
+
+	log, err := r.fetchLogs(ctx, sp, pod, container.name, false)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if log.Path == "" {
+		return logs, errs
+	}
+	current := report.ContainerLog{
+		Name:    container.name,
+		Init:    container.init,
+		Primary: container.primary,
+		Log:     log,
+	}
+	if status != nil {
+		current.RestartCount = status.RestartCount
+		current.Termination = newTermination(status.State.Terminated)
+	}
+
+	return append(logs, current), errs
+}
+
+// fetchLogs streams logs of the container to a file in the spool. If the stream breaks,
+// the log read so far is returned together with the error, unless nothing has been read or stored.
+func (r reporter) fetchLogs(ctx context.Context, sp *spool.Spool, pod corev1.Pod, container string, previous bool) (report.Log, error) {
+	logf.Info(fmt.Sprintf("Extracting logs from container %s from pod %s from namespace %s (previous: %t)", container, pod.Name, pod.Namespace, previous))
+	req := r.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+	}).Context(ctx)
+
+	file, err := sp.Create()
+	if err != nil {
+		return report.Log{}, err
+	}
+
+	var log report.Log
+	consumeErr := ConsumeRequest(req, file)
+	switch {
+	case consumeErr == nil:
+		log, err = file.Close()
+	case file.Received() == 0:
+		// there's nothing worth attaching, e.g. if logs of the previous instance don't exist
+		file.Close()
+		return report.Log{}, errors.Wrapf(consumeErr, "while reading request from container %s in pod %s in namespace %s", container, pod.Name, pod.Namespace)
+	default:
+		log, err = file.CloseInterrupted(consumeErr)
+	}
+	if err != nil {
+		return report.Log{}, errors.Wrapf(err, "while storing logs from container %s in pod %s in namespace %s", container, pod.Name, pod.Namespace)
+	}
+	if consumeErr != nil {
+		// the part of the logs read before the stream broke, e.g. because of the pod timeout, is still returned
+		return log, errors.Wrapf(consumeErr, "while reading request from container %s in pod %s in namespace %s", container, pod.Name, pod.Namespace)
+	}
+	return log, nil
+}
+
+func findContainerStatus(pod corev1.Pod, container selectedContainer) *corev1.ContainerStatus {
+	statuses := pod.Status.ContainerStatuses
+	if container.init {
+		statuses = pod.Status.InitContainerStatuses
+	}
+
+	for i := range statuses {
+		if statuses[i].Name == container.name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+func newTermination(state *corev1.ContainerStateTerminated) *report.Termination {
+	if state == nil {
+		return nil
+	}
+	return &report.Termination{
+		ExitCode: state.ExitCode,
+		Reason:   state.Reason,
+		Message:  state.Message,
+	}
+}
+
+// decisiveExecution returns index of the execution which decided the final status of the test:
+// the last one which ended with the phase corresponding to that status, or simply the last one.
+// It returns -1 if the test has no executions.
+func decisiveExecution(result octopusTypes.TestResult) int {
+	var phase corev1.PodPhase
+	switch result.Status {
+	case octopusTypes.TestSucceeded:
+		phase = corev1.PodSucceeded
+	case octopusTypes.TestFailed:
+		phase = corev1.PodFailed
+	}
+
+	if phase != "" {
+		for i := len(result.Executions) - 1; i >= 0; i-- {
+			if result.Executions[i].PodPhase == phase {
+				return i
+			}
+		}
+	}
+
+	return len(result.Executions) - 1
+}
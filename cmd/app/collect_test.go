@@ -0,0 +1,185 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/spool"
+)
+
+func Test_decisiveExecution(t *testing.T) {
+	tests := []struct {
+		name   string
+		result octopusTypes.TestResult
+		want   int
+	}{
+		{
+			name:   "no executions",
+			result: octopusTypes.TestResult{Status: octopusTypes.TestSkipped},
+			want:   -1,
+		},
+		{
+			name: "succeeded on retry",
+			result: octopusTypes.TestResult{Status: octopusTypes.TestSucceeded, Executions: []octopusTypes.TestExecution{
+				{ID: "1", PodPhase: corev1.PodFailed},
+				{ID: "2", PodPhase: corev1.PodSucceeded},
+			}},
+			want: 1,
+		},
+		{
+			name: "failed once out of many runs",
+			result: octopusTypes.TestResult{Status: octopusTypes.TestFailed, Executions: []octopusTypes.TestExecution{
+				{ID: "1", PodPhase: corev1.PodSucceeded},
+				{ID: "2", PodPhase: corev1.PodFailed},
+				{ID: "3", PodPhase: corev1.PodSucceeded},
+			}},
+			want: 1,
+		},
+		{
+			name: "unknown status falls back to the last execution",
+			result: octopusTypes.TestResult{Status: octopusTypes.TestUnknown, Executions: []octopusTypes.TestExecution{
+				{ID: "1", PodPhase: corev1.PodFailed},
+				{ID: "2", PodPhase: corev1.PodUnknown},
+			}},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decisiveExecution(tt.result); got != tt.want {
+				t.Errorf("decisiveExecution() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newSuite(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	at := func(minutes int) *metav1.Time {
+		tm := metav1.NewTime(time.Date(2020, 7, 14, 12, minutes, 0, 0, time.UTC))
+		return &tm
+	}
+	cts := octopusTypes.ClusterTestSuite{
+		ObjectMeta: metav1.ObjectMeta{Name: "testsuite-all"},
+		Status: octopusTypes.TestSuiteStatus{
+			StartTime:      at(0),
+			CompletionTime: at(30),
+			Conditions: []octopusTypes.TestSuiteCondition{
+				{Type: octopusTypes.SuiteFailed, Status: octopusTypes.StatusTrue, Reason: "TestFailed"},
+			},
+			Results: []octopusTypes.TestResult{
+				{
+					Name:      "serverless",
+					Namespace: "kyma-system",
+					Status:    octopusTypes.TestFailed,
+					Executions: []octopusTypes.TestExecution{
+						{ID: "serverless-0", StartTime: at(1), CompletionTime: at(3)},
+						{ID: "serverless-1", StartTime: at(4), CompletionTime: at(9)},
+					},
+				},
+				{
+					Name:       "monitoring",
+					Namespace:  "kyma-system",
+					Status:     octopusTypes.TestRunning,
+					Executions: []octopusTypes.TestExecution{{ID: "monitoring-0", StartTime: at(1)}},
+				},
+			},
+		},
+	}
+
+	suite := newSuite(cts)
+
+	g.Expect(suite.Name).To(gomega.Equal("testsuite-all"))
+	g.Expect(suite.StartTime).To(gomega.Equal(at(0).Time))
+	g.Expect(suite.Conditions).To(gomega.Equal([]report.Condition{{Type: "Failed", Status: "True", Reason: "TestFailed"}}))
+	g.Expect(suite.Tests).To(gomega.Equal([]report.TestSummary{
+		{Name: "serverless", Namespace: "kyma-system", Status: "Failed", Duration: 8 * time.Minute, Retries: 1},
+		{Name: "monitoring", Namespace: "kyma-system", Status: "Running"},
+	}))
+}
+
+func Test_findContainerStatus(t *testing.T) {
+	pod := corev1.Pod{Status: corev1.PodStatus{
+		InitContainerStatuses: []corev1.ContainerStatus{{Name: "test", RestartCount: 1}},
+		ContainerStatuses:     []corev1.ContainerStatus{{Name: "test", RestartCount: 2}},
+	}}
+
+	tests := []struct {
+		name      string
+		container selectedContainer
+		want      *corev1.ContainerStatus
+	}{
+		{
+			name:      "finds status of container",
+			container: selectedContainer{name: "test"},
+			want:      &corev1.ContainerStatus{Name: "test", RestartCount: 2},
+		},
+		{
+			name:      "finds status of init container",
+			container: selectedContainer{name: "test", init: true},
+			want:      &corev1.ContainerStatus{Name: "test", RestartCount: 1},
+		},
+		{
+			name:      "returns nil for unknown container",
+			container: selectedContainer{name: "unknown"},
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			g.Expect(findContainerStatus(pod, tt.container)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestReporter_collectLogs(t *testing.T) {
+	t.Run("returns problem of every failing pod in the report order", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		var results []report.TestResult
+		for i := 0; i < 20; i++ {
+			results = append(results, report.TestResult{
+				Name:       fmt.Sprintf("test-%d", i),
+				Namespace:  "default",
+				Executions: []report.Execution{{ID: fmt.Sprintf("pod-%d", i)}},
+				Routes:     []report.Route{{ChannelID: "chan"}},
+			})
+		}
+
+		onlySidecar := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-3", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "istio-proxy"}}},
+		}
+
+		r := reporter{
+			clientset:   fake.NewSimpleClientset(onlySidecar),
+			parallelism: 4,
+			podTimeout:  time.Second,
+		}
+
+		sp, err := spool.New(0)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		defer func() {
+			g.Expect(sp.Remove()).To(gomega.Succeed())
+		}()
+
+		problems := r.collectLogs(sp, results)
+		g.Expect(problems).To(gomega.HaveLen(20))
+		for i, p := range problems {
+			g.Expect(p.Test).To(gomega.Equal(fmt.Sprintf("test-%d", i)))
+			g.Expect(p.Execution).To(gomega.Equal(fmt.Sprintf("pod-%d", i)))
+			g.Expect(p.Routes).To(gomega.Equal([]report.Route{{ChannelID: "chan"}}))
+		}
+		g.Expect(problems[3].Message).To(gomega.ContainSubstring("while selecting containers of pod pod-3"))
+	})
+}
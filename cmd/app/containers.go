@@ -0,0 +1,117 @@
+package app
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// annotationLogContainer lets test author pick the primary container of the testing pod
+	annotationLogContainer = "joby.kyma-project.io/log-container"
+
+	istioProxyContainer = "istio-proxy"
+
+	containersPrimary = "primary"
+	containersAll     = "all"
+)
+
+// containerPolicy decides logs of which containers of the testing pod are collected.
+type containerPolicy struct {
+	// all collects every application container, not only the primary one
+	all            bool
+	initContainers bool
+	istioProxy     bool
+}
+
+type selectedContainer struct {
+	name    string
+	init    bool
+	primary bool
+}
+
+func newContainerPolicy(conf *config) (containerPolicy, error) {
+	policy := containerPolicy{
+		initContainers: conf.InitContainers,
+		istioProxy:     conf.IstioProxy,
+	}
+
+	switch conf.Containers {
+	case containersPrimary:
+	case containersAll:
+		policy.all = true
+	default:
+		return containerPolicy{}, fmt.Errorf("unknown containers policy %s, expected %s or %s", conf.Containers, containersPrimary, containersAll)
+	}
+
+	return policy, nil
+}
+
+// selectContainers returns containers whose logs should be collected, in order: init containers,
+// the primary container, the rest of application containers and the istio-proxy sidecar.
+// If the primary container can't be determined, every application container is collected.
+func (p containerPolicy) selectContainers(pod corev1.Pod) ([]selectedContainer, error) {
+	var selected []selectedContainer
+
+	if p.initContainers {
+		for _, cont := range pod.Spec.InitContainers {
+			selected = append(selected, selectedContainer{name: cont.Name, init: true})
+		}
+	}
+
+	var appContainers []string
+	hasIstioProxy := false
+	for _, cont := range pod.Spec.Containers {
+		if cont.Name == istioProxyContainer {
+			hasIstioProxy = true
+			continue
+		}
+		appContainers = append(appContainers, cont.Name)
+	}
+
+	primary, err := primaryContainer(pod, appContainers)
+	if err != nil {
+		return nil, err
+	}
+
+	if primary != "" {
+		selected = append(selected, selectedContainer{name: primary, primary: true})
+	}
+
+	for _, name := range appContainers {
+		if name == primary || (primary != "" && !p.all) {
+			continue
+		}
+		selected = append(selected, selectedContainer{name: name})
+	}
+
+	// the sidecar may be the primary container itself if the annotation names it
+	if p.istioProxy && hasIstioProxy && primary != istioProxyContainer {
+		selected = append(selected, selectedContainer{name: istioProxyContainer})
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("there's no container to collect logs from in pod %s in namespace %s", pod.Name, pod.Namespace)
+	}
+
+	return selected, nil
+}
+
+// primaryContainer returns container set in the pod annotation or the only application container.
+// It returns empty string if the pod has many application containers and none of them is picked.
+func primaryContainer(pod corev1.Pod, appContainers []string) (string, error) {
+	if name, ok := pod.Annotations[annotationLogContainer]; ok {
+		for _, cont := range pod.Spec.Containers {
+			if cont.Name == name {
+				return name, nil
+			}
+		}
+		return "", fmt.Errorf("container %s set in %s annotation doesn't exist in pod %s in namespace %s", name, annotationLogContainer, pod.Name, pod.Namespace)
+	}
+
+	if len(appContainers) == 1 {
+		return appContainers[0], nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestContainerPolicy_selectContainers(t *testing.T) {
+	newPod := func(annotations map[string]string, initContainers []string, containers ...string) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns", Annotations: annotations}}
+		for _, name := range initContainers {
+			pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{Name: name})
+		}
+		for _, name := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: name})
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name    string
+		policy  containerPolicy
+		pod     corev1.Pod
+		want    []selectedContainer
+		wantErr bool
+	}{
+		{
+			name:   "picks the only non-istio container",
+			policy: containerPolicy{},
+			pod:    newPod(nil, []string{"setup"}, "test", "istio-proxy"),
+			want:   []selectedContainer{{name: "test", primary: true}},
+		},
+		{
+			name:   "picks container from annotation",
+			policy: containerPolicy{},
+			pod:    newPod(map[string]string{annotationLogContainer: "test"}, nil, "db", "test"),
+			want:   []selectedContainer{{name: "test", primary: true}},
+		},
+		{
+			name:   "collects every application container if primary is ambiguous",
+			policy: containerPolicy{},
+			pod:    newPod(nil, nil, "db", "test", "istio-proxy"),
+			want:   []selectedContainer{{name: "db"}, {name: "test"}},
+		},
+		{
+			name:   "collects all containers with init containers and istio-proxy",
+			policy: containerPolicy{all: true, initContainers: true, istioProxy: true},
+			pod:    newPod(map[string]string{annotationLogContainer: "test"}, []string{"setup"}, "db", "test", "istio-proxy"),
+			want: []selectedContainer{
+				{name: "setup", init: true},
+				{name: "test", primary: true},
+				{name: "db"},
+				{name: "istio-proxy"},
+			},
+		},
+		{
+			name:   "collects istio-proxy once if it's the primary container",
+			policy: containerPolicy{istioProxy: true},
+			pod:    newPod(map[string]string{annotationLogContainer: "istio-proxy"}, nil, "test", "istio-proxy"),
+			want:   []selectedContainer{{name: "istio-proxy", primary: true}},
+		},
+		{
+			name:    "errors on annotation with unknown container",
+			policy:  containerPolicy{},
+			pod:     newPod(map[string]string{annotationLogContainer: "unknown"}, nil, "test"),
+			wantErr: true,
+		},
+		{
+			name:    "errors if there's nothing to collect",
+			policy:  containerPolicy{},
+			pod:     newPod(nil, nil, "istio-proxy"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			got, err := tt.policy.selectContainers(tt.pod)
+			if tt.wantErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(got).To(gomega.Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	logf "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite"
+	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+)
+
+// maxReportRetries limits how many times reporting of a suite is retried if it failed before anything has been sent.
+const maxReportRetries = 5
+
+// reportMarker persists which ClusterTestSuites have been reported.
+type reportMarker interface {
+	MarkReported(suite string) error
+}
+
+// dryRunMarker doesn't persist anything, so that a dry run doesn't keep suites from being reported for real.
+type dryRunMarker struct{}
+
+func (dryRunMarker) MarkReported(suite string) error {
+	return nil
+}
+
+func newReportMarker(conf *config, dynamicCli dynamic.Interface) reportMarker {
+	if conf.DryRun {
+		return dryRunMarker{}
+	}
+	return clustertestsuite.NewReportMarker(dynamicCli)
+}
+
+// controller watches ClusterTestSuites and runs the report pipeline once for every completed suite
+// which hasn't been marked as reported yet.
+type controller struct {
+	informer cache.SharedIndexInformer
+	queue    workqueue.RateLimitingInterface
+	marker   reportMarker
+	selector suiteSelector
+	process  func(cts octopusTypes.ClusterTestSuite) error
+	// since is the completion time from which suites are reported, older ones are assumed to be reported
+	// by a previous run of joby
+	since time.Time
+
+	// reported covers suites whose marker couldn't be saved. It's accessed only by the single worker,
+	// so it doesn't need a lock
+	reported map[types.UID]struct{}
+}
+
+func newController(informer cache.SharedIndexInformer, marker reportMarker, selector suiteSelector, process func(cts octopusTypes.ClusterTestSuite) error) *controller {
+	c := &controller{
+		informer: informer,
+		queue:    workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		marker:   marker,
+		selector: selector,
+		process:  process,
+		since:    time.Now(),
+		reported: map[types.UID]struct{}{},
+	}
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueue,
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueue(newObj)
+		},
+	})
+
+	return c
+}
+
+func (c *controller) enqueue(obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		logf.Errorf("unexpected object of type %T in ClusterTestSuite informer", obj)
+		return
+	}
+
+	cts, err := clustertestsuite.FromUnstructured(u)
+	if err != nil {
+		logf.Errorf("while converting ClusterTestSuite %s: %s", u.GetName(), err)
+		return
+	}
+
+	// suites are filtered by completion time only in sync, as the time is known once the informer has synced
+	if cts.Status.CompletionTime == nil || clustertestsuite.IsReported(cts) || !c.selector.matches(cts) {
+		return
+	}
+
+	key, err := cache.MetaNamespaceKeyFunc(u)
+	if err != nil {
+		logf.Errorf("while getting key of ClusterTestSuite %s: %s", u.GetName(), err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// completedAfter tells whether ClusterTestSuite has been completed after given time.
+func completedAfter(cts octopusTypes.ClusterTestSuite, t time.Time) bool {
+	return cts.Status.CompletionTime != nil && !cts.Status.CompletionTime.Time.Before(t)
+}
+
+// resumeTime returns completion time of the newest suite marked as reported, so that suites completed
+// while the controller wasn't running are reported as well. If no suite has been marked, e.g. on the first run,
+// only suites completed from now on are reported.
+func resumeTime(suites []octopusTypes.ClusterTestSuite, now time.Time) time.Time {
+	var newest *time.Time
+	for _, cts := range suites {
+		if !clustertestsuite.IsReported(cts) || cts.Status.CompletionTime == nil {
+			continue
+		}
+		if completion := cts.Status.CompletionTime.Time; newest == nil || completion.After(*newest) {
+			newest = &completion
+		}
+	}
+	if newest == nil {
+		return now
+	}
+	return *newest
+}
+
+func (c *controller) Run(stopCh <-chan struct{}) error {
+	defer c.queue.ShutDown()
+
+	logf.Info("Starting ClusterTestSuite controller")
+	go c.informer.Run(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, c.informer.HasSynced) {
+		return errors.New("while waiting for ClusterTestSuite informer to sync")
+	}
+
+	var suites []octopusTypes.ClusterTestSuite
+	for _, obj := range c.informer.GetStore().List() {
+		cts, err := clustertestsuite.FromUnstructured(obj.(*unstructured.Unstructured))
+		if err != nil {
+			logf.Errorf("while converting ClusterTestSuite: %s", err)
+			continue
+		}
+		suites = append(suites, cts)
+	}
+	c.since = resumeTime(suites, c.since)
+	logf.Infof("reporting ClusterTestSuites completed since %s", c.since.Format(time.RFC3339))
+
+	go wait.Until(c.runWorker, time.Second, stopCh)
+
+	<-stopCh
+	logf.Info("Shutting down ClusterTestSuite controller")
+	return nil
+}
+
+func (c *controller) runWorker() {
+	for c.processNextItem() {
+	}
+}
+
+func (c *controller) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	err := c.sync(key.(string))
+	if err != nil && isUnsent(err) && c.queue.NumRequeues(key) < maxReportRetries {
+		logf.Warnf("%s, retrying", err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	if err != nil {
+		logf.Error(err)
+	}
+	return true
+}
+
+func (c *controller) sync(key string) error {
+	obj, exists, err := c.informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return errors.Wrapf(err, "while getting ClusterTestSuite %s from cache", key)
+	}
+	if !exists {
+		return nil
+	}
+
+	cts, err := clustertestsuite.FromUnstructured(obj.(*unstructured.Unstructured))
+	if err != nil {
+		return errors.Wrapf(err, "while converting ClusterTestSuite %s", key)
+	}
+
+	if _, ok := c.reported[cts.UID]; ok || clustertestsuite.IsReported(cts) || !completedAfter(cts, c.since) {
+		return nil
+	}
+
+	logf.Infof("ClusterTestSuite %s has been completed, reporting", cts.Name)
+	err = c.process(cts)
+	if err != nil && isUnsent(err) {
+		// nothing has been sent, so the suite can be reported again
+		return errors.Wrapf(err, "while reporting ClusterTestSuite %s", cts.Name)
+	}
+
+	// the suite is marked as reported regardless of the outcome, so that a partially sent report is never duplicated
+	c.reported[cts.UID] = struct{}{}
+	if err := c.marker.MarkReported(cts.Name); err != nil {
+		logf.Error(err)
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "while reporting ClusterTestSuite %s", cts.Name)
+	}
+	return nil
+}
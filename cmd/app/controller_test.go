@@ -0,0 +1,246 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite"
+	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+)
+
+func Test_completedAfter(t *testing.T) {
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := metav1.NewTime(start.Add(-time.Minute))
+	after := metav1.NewTime(start.Add(time.Minute))
+
+	tests := []struct {
+		name string
+		cts  octopusTypes.ClusterTestSuite
+		want bool
+	}{
+		{
+			name: "not completed suite",
+			cts:  octopusTypes.ClusterTestSuite{},
+			want: false,
+		},
+		{
+			name: "suite completed before given time",
+			cts:  octopusTypes.ClusterTestSuite{Status: octopusTypes.TestSuiteStatus{CompletionTime: &before}},
+			want: false,
+		},
+		{
+			name: "suite completed after given time",
+			cts:  octopusTypes.ClusterTestSuite{Status: octopusTypes.TestSuiteStatus{CompletionTime: &after}},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completedAfter(tt.cts, start); got != tt.want {
+				t.Errorf("completedAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resumeTime(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	completed := func(minutes int, reported bool) octopusTypes.ClusterTestSuite {
+		completion := metav1.NewTime(now.Add(time.Duration(minutes) * time.Minute))
+		cts := octopusTypes.ClusterTestSuite{Status: octopusTypes.TestSuiteStatus{CompletionTime: &completion}}
+		if reported {
+			cts.Annotations = map[string]string{clustertestsuite.ReportedAnnotation: "2020-06-01T12:00:00Z"}
+		}
+		return cts
+	}
+
+	tests := []struct {
+		name   string
+		suites []octopusTypes.ClusterTestSuite
+		want   time.Time
+	}{
+		{
+			name:   "starts now if no suite has been reported",
+			suites: []octopusTypes.ClusterTestSuite{completed(-30, false), {}},
+			want:   now,
+		},
+		{
+			name:   "resumes from the newest reported suite",
+			suites: []octopusTypes.ClusterTestSuite{completed(-30, true), completed(-10, false), completed(-20, true)},
+			want:   now.Add(-20 * time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resumeTime(tt.suites, now); !got.Equal(tt.want) {
+				t.Errorf("resumeTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newReportMarker(t *testing.T) {
+	tests := []struct {
+		name         string
+		conf         *config
+		wantReported bool
+	}{
+		{name: "marks suite as reported", conf: &config{}, wantReported: true},
+		{name: "doesn't mark suite in the dry-run mode", conf: &config{DryRun: true}, wantReported: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			suite := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "testing.kyma-project.io/v1alpha1",
+				"kind":       "ClusterTestSuite",
+				"metadata":   map[string]interface{}{"name": "testsuite"},
+			}}
+			dynamicCli := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), suite)
+
+			g.Expect(newReportMarker(tt.conf, dynamicCli).MarkReported("testsuite")).To(gomega.Succeed())
+
+			u, err := dynamicCli.Resource(octopusTypes.SchemeGroupVersion.WithResource("clustertestsuites")).Get("testsuite", metav1.GetOptions{})
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			cts, err := clustertestsuite.FromUnstructured(u)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(clustertestsuite.IsReported(cts)).To(gomega.Equal(tt.wantReported))
+		})
+	}
+}
+
+// fakeMarker records names of suites marked as reported.
+type fakeMarker struct {
+	marked []string
+}
+
+func (f *fakeMarker) MarkReported(suite string) error {
+	f.marked = append(f.marked, suite)
+	return nil
+}
+
+func TestController_sync(t *testing.T) {
+	completion := metav1.NewTime(time.Now().Add(time.Minute))
+	newSuite := func(annotations map[string]string) *unstructured.Unstructured {
+		cts := octopusTypes.ClusterTestSuite{
+			TypeMeta:   metav1.TypeMeta{APIVersion: octopusTypes.SchemeGroupVersion.String(), Kind: "ClusterTestSuite"},
+			ObjectMeta: metav1.ObjectMeta{Name: "testsuite", UID: "uid-1", Annotations: annotations},
+			Status:     octopusTypes.TestSuiteStatus{CompletionTime: &completion},
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&cts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &unstructured.Unstructured{Object: obj}
+	}
+
+	t.Run("reports completed suite exactly once", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		var reported []string
+		marker := &fakeMarker{}
+		informer := clustertestsuite.NewInformer(dynamicFake.NewSimpleDynamicClient(runtime.NewScheme()), 0)
+		c := newController(informer, marker, suiteSelector{}, func(cts octopusTypes.ClusterTestSuite) error {
+			reported = append(reported, cts.Name)
+			return nil
+		})
+		g.Expect(informer.GetIndexer().Add(newSuite(nil))).To(gomega.Succeed())
+
+		g.Expect(c.sync("testsuite")).To(gomega.Succeed())
+		g.Expect(c.sync("testsuite")).To(gomega.Succeed())
+		g.Expect(reported).To(gomega.Equal([]string{"testsuite"}))
+		g.Expect(marker.marked).To(gomega.Equal([]string{"testsuite"}))
+	})
+
+	t.Run("skips suite marked as reported", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		informer := clustertestsuite.NewInformer(dynamicFake.NewSimpleDynamicClient(runtime.NewScheme()), 0)
+		c := newController(informer, &fakeMarker{}, suiteSelector{}, func(cts octopusTypes.ClusterTestSuite) error {
+			t.Errorf("unexpected report of %s", cts.Name)
+			return nil
+		})
+		g.Expect(informer.GetIndexer().Add(newSuite(map[string]string{clustertestsuite.ReportedAnnotation: "2020-06-01T12:00:00Z"}))).To(gomega.Succeed())
+
+		g.Expect(c.sync("testsuite")).To(gomega.Succeed())
+	})
+
+	t.Run("skips suite completed before the controller resumed", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		informer := clustertestsuite.NewInformer(dynamicFake.NewSimpleDynamicClient(runtime.NewScheme()), 0)
+		c := newController(informer, &fakeMarker{}, suiteSelector{}, func(cts octopusTypes.ClusterTestSuite) error {
+			t.Errorf("unexpected report of %s", cts.Name)
+			return nil
+		})
+		c.since = completion.Add(time.Minute)
+		g.Expect(informer.GetIndexer().Add(newSuite(nil))).To(gomega.Succeed())
+
+		g.Expect(c.sync("testsuite")).To(gomega.Succeed())
+	})
+
+	t.Run("reports suite again if nothing has been sent", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		attempts := 0
+		marker := &fakeMarker{}
+		informer := clustertestsuite.NewInformer(dynamicFake.NewSimpleDynamicClient(runtime.NewScheme()), 0)
+		c := newController(informer, marker, suiteSelector{}, func(cts octopusTypes.ClusterTestSuite) error {
+			attempts++
+			if attempts == 1 {
+				return unsentError{errors.New("no space left on device")}
+			}
+			return nil
+		})
+		g.Expect(informer.GetIndexer().Add(newSuite(nil))).To(gomega.Succeed())
+
+		err := c.sync("testsuite")
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(isUnsent(err)).To(gomega.BeTrue())
+		g.Expect(marker.marked).To(gomega.BeEmpty())
+
+		g.Expect(c.sync("testsuite")).To(gomega.Succeed())
+		g.Expect(attempts).To(gomega.Equal(2))
+		g.Expect(marker.marked).To(gomega.Equal([]string{"testsuite"}))
+	})
+
+	t.Run("doesn't report suite again if it may have been partially sent", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		attempts := 0
+		marker := &fakeMarker{}
+		informer := clustertestsuite.NewInformer(dynamicFake.NewSimpleDynamicClient(runtime.NewScheme()), 0)
+		c := newController(informer, marker, suiteSelector{}, func(cts octopusTypes.ClusterTestSuite) error {
+			attempts++
+			return errors.New("while dispatching report")
+		})
+		g.Expect(informer.GetIndexer().Add(newSuite(nil))).To(gomega.Succeed())
+
+		err := c.sync("testsuite")
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(isUnsent(err)).To(gomega.BeFalse())
+		g.Expect(c.sync("testsuite")).To(gomega.Succeed())
+		g.Expect(attempts).To(gomega.Equal(1))
+		g.Expect(marker.marked).To(gomega.Equal([]string{"testsuite"}))
+	})
+
+	t.Run("ignores deleted suite", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		informer := clustertestsuite.NewInformer(dynamicFake.NewSimpleDynamicClient(runtime.NewScheme()), 0)
+		c := newController(informer, &fakeMarker{}, suiteSelector{}, func(cts octopusTypes.ClusterTestSuite) error {
+			t.Errorf("unexpected report of %s", cts.Name)
+			return nil
+		})
+
+		g.Expect(c.sync("testsuite")).To(gomega.Succeed())
+	})
+}
@@ -0,0 +1,184 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	logf "github.com/sirupsen/logrus"
+	"k8s.io/client-go/dynamic"
+
+	pkgConfig "github.com/kyma-project/test-infra/test-log-collector/pkg/config"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/hyperscaler"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite"
+	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+)
+
+// explanation tells how a single test result would be routed.
+type explanation struct {
+	test      string
+	namespace string
+	status    string
+	fallback  bool
+	routes    []explainedRoute
+	// problem is set if the test couldn't be routed as usual, e.g. because its labels couldn't be fetched
+	problem string
+}
+
+// explainedRoute is a route matching the test, together with the decision whether it reports the test.
+type explainedRoute struct {
+	config pkgConfig.LogsScrapingConfig
+	// reported is empty if the route doesn't report the test, otherwise it's either logs or summary
+	reported string
+	reason   string
+}
+
+// runExplain prints how tests of the ClusterTestSuite from the file, or of the live ones picked by the selector,
+// would be routed. Nothing is collected or sent.
+func runExplain(out io.Writer, suiteFile string, dynamicCli dynamic.Interface, sel suiteSelector, rep reporter) error {
+	var suites []octopusTypes.ClusterTestSuite
+	if suiteFile != "" {
+		cts, err := clustertestsuite.LoadFromFile(suiteFile)
+		if err != nil {
+			return errors.Wrap(err, "while loading ClusterTestSuite")
+		}
+		suites = append(suites, cts)
+	} else {
+		ctsList, err := clustertestsuite.New(dynamicCli, 20*time.Second).List()
+		if err != nil {
+			return errors.Wrapf(err, "while listing ClusterTestSuites")
+		}
+		suites, err = selectClusterTestSuites(ctsList, sel)
+		if err != nil {
+			return errors.Wrap(err, "while selecting ClusterTestSuites")
+		}
+	}
+
+	for i, cts := range suites {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		if err := writeExplanations(out, cts.Name, string(rep.platform), rep.explain(cts)); err != nil {
+			return errors.Wrapf(err, "while writing explanation of ClusterTestSuite %s", cts.Name)
+		}
+	}
+	return nil
+}
+
+// offlineLabels stands in for TestDefinitions when the suite is explained without a cluster.
+type offlineLabels struct{}
+
+func (offlineLabels) Labels(namespace, name string) (map[string]string, error) {
+	return nil, errors.New("TestDefinitions can't be read when the suite is explained from a file")
+}
+
+// runOfflineExplain explains the ClusterTestSuite from the file without connecting to the cluster.
+// The platform is taken from the configuration, routes matching on labels are reported as problems
+// and LogDispatchingPolicies are ignored.
+func runOfflineExplain(out io.Writer, conf *config, resolver pkgConfig.ChannelResolver) error {
+	if conf.Policies {
+		if conf.ConfigLocation == "" {
+			return errors.New("APP_CONFIG_LOCATION has to be set to explain a suite from a file, LogDispatchingPolicies can't be read without a cluster")
+		}
+		logf.Warn("LogDispatchingPolicies can't be read without a cluster, only routes from the configuration file are explained")
+	}
+
+	offline := *conf
+	offline.Policies = false
+	dispatching, err := loadDispatching(&offline, resolver, nil)
+	if err != nil {
+		return err
+	}
+
+	rep := reporter{
+		testDefs: offlineLabels{},
+		state:    newStateHolder(dispatchingState{dispatching: dispatching}),
+		platform: hyperscaler.Platform(conf.Platform),
+	}
+	return runExplain(out, conf.SuiteFile, nil, suiteSelector{}, rep)
+}
+
+// explain routes every test of the suite the same way report does.
+func (r reporter) explain(cts octopusTypes.ClusterTestSuite) []explanation {
+	dispatching := r.state.Load().dispatching
+
+	var explanations []explanation
+	for _, result := range cts.Status.Results {
+		testCase, err := r.newTestCase(dispatching, result)
+		exp := explainResult(dispatching, testCase, result)
+		if err != nil {
+			problem := errors.Wrap(err, "while getting labels for routing, routing without them").Error()
+			if exp.problem != "" {
+				problem += "; " + exp.problem
+			}
+			exp.problem = problem
+		}
+		explanations = append(explanations, exp)
+	}
+	return explanations
+}
+
+func explainResult(dispatching pkgConfig.Dispatching, testCase pkgConfig.TestCase, result octopusTypes.TestResult) explanation {
+	exp := explanation{
+		test:      result.Name,
+		namespace: result.Namespace,
+		status:    string(result.Status),
+	}
+
+	testConfigs, fallback, err := dispatching.MatchTestCase(testCase)
+	if err != nil {
+		exp.problem = err.Error()
+		return exp
+	}
+	exp.fallback = fallback
+
+	for _, testConfig := range testConfigs {
+		route, reason := newRoute(testConfig, result)
+		explained := explainedRoute{config: testConfig, reason: reason}
+		if reason == "" {
+			explained.reported = string(pkgConfig.ReportLogs)
+			if route.SummaryOnly {
+				explained.reported = string(pkgConfig.ReportSummary)
+			}
+		}
+		exp.routes = append(exp.routes, explained)
+	}
+	return exp
+}
+
+func writeExplanations(out io.Writer, suite, platform string, explanations []explanation) error {
+	fmt.Fprintf(out, "ClusterTestSuite %s on platform %s\n", suite, platform)
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TEST\tNAMESPACE\tSTATUS\tROUTE\tCHANNEL\tREPORTED\tFALLBACK")
+	for _, exp := range explanations {
+		fallback := "no"
+		if exp.fallback {
+			fallback = "yes"
+		}
+
+		if len(exp.routes) == 0 {
+			fmt.Fprintf(w, "%s\t%s\t%s\t-\t-\tno: %s\t%s\n", exp.test, exp.namespace, exp.status, exp.problem, fallback)
+			continue
+		}
+		for _, route := range exp.routes {
+			reported := route.reported
+			if reported == "" {
+				reported = "no: " + route.reason
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", exp.test, exp.namespace, exp.status, route.config.Location(), route.config.ChannelName, reported, fallback)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, exp := range explanations {
+		if len(exp.routes) > 0 && exp.problem != "" {
+			fmt.Fprintf(out, "%s: %s\n", exp.test, exp.problem)
+		}
+	}
+	return nil
+}
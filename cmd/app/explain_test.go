@@ -0,0 +1,116 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	pkgConfig "github.com/kyma-project/test-infra/test-log-collector/pkg/config"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/hyperscaler"
+)
+
+const explainedSuite = `apiVersion: testing.kyma-project.io/v1alpha1
+kind: ClusterTestSuite
+metadata:
+  name: testsuite-all
+status:
+  results:
+    - name: serverless-long
+      namespace: serverless
+      status: Failed
+      executions:
+        - id: serverless-long-0
+    - name: monitoring
+      namespace: kyma-system
+      status: Succeeded
+      executions:
+        - id: monitoring-0
+    - name: rafter
+      namespace: rafter
+      status: Failed
+`
+
+func TestRunExplain(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "explain")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	suiteFile := filepath.Join(dir, "suite.yaml")
+	g.Expect(ioutil.WriteFile(suiteFile, []byte(explainedSuite), 0644)).To(gomega.Succeed())
+
+	dispatching := pkgConfig.Dispatching{Config: []pkgConfig.LogsScrapingConfig{
+		{ChannelName: "#default", TestCases: []string{"default"}, OnlyReportFailure: true, Line: 3},
+		{ChannelName: "#serverless", Namespaces: []string{"serverless"}, Line: 6},
+		{ChannelName: "#triage", TestCases: []string{"serverless-*"}, Statuses: map[string]pkgConfig.ReportMode{"Failed": pkgConfig.ReportSummary}, Source: "LogDispatchingPolicy triage"},
+	}}
+	rep := reporter{
+		state:    newStateHolder(dispatchingState{dispatching: dispatching}),
+		platform: hyperscaler.Gke,
+	}
+
+	out := &bytes.Buffer{}
+	err = runExplain(out, suiteFile, nil, suiteSelector{}, rep)
+
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(out.String()).To(gomega.Equal(`ClusterTestSuite testsuite-all on platform GKE
+TEST             NAMESPACE    STATUS     ROUTE                                                  CHANNEL      REPORTED                                     FALLBACK
+serverless-long  serverless   Failed     LogDispatchingPolicy triage, route of channel #triage  #triage      summary                                      no
+serverless-long  serverless   Failed     line 6                                                 #serverless  logs                                         no
+monitoring       kyma-system  Succeeded  line 3                                                 #default     no: channel doesn't report status Succeeded  yes
+rafter           rafter       Failed     line 3                                                 #default     no: it has no executions                     yes
+`))
+}
+
+func TestRunOfflineExplain(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "explain")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	suiteFile := filepath.Join(dir, "suite.yaml")
+	g.Expect(ioutil.WriteFile(suiteFile, []byte(explainedSuite), 0644)).To(gomega.Succeed())
+	configFile := filepath.Join(dir, "config.yaml")
+	g.Expect(ioutil.WriteFile(configFile, []byte(`apiVersion: joby.kyma-project.io/v1alpha1
+kind: DispatchingConfig
+routes:
+  - channelName: "#default"
+    testCases:
+      - default
+  - channelName: "#serverless"
+    labels:
+      owner: serverless
+`), 0644)).To(gomega.Succeed())
+
+	t.Run("routes without labels", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		out := &bytes.Buffer{}
+		err := runOfflineExplain(out, &config{ConfigLocation: configFile, SuiteFile: suiteFile, Platform: "GKE"}, nil)
+
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(out.String()).To(gomega.Equal(`ClusterTestSuite testsuite-all on platform GKE
+TEST             NAMESPACE    STATUS     ROUTE   CHANNEL   REPORTED                  FALLBACK
+serverless-long  serverless   Failed     line 4  #default  logs                      yes
+monitoring       kyma-system  Succeeded  line 4  #default  logs                      yes
+rafter           rafter       Failed     line 4  #default  no: it has no executions  yes
+serverless-long: while getting labels for routing, routing without them: TestDefinitions can't be read when the suite is explained from a file
+monitoring: while getting labels for routing, routing without them: TestDefinitions can't be read when the suite is explained from a file
+rafter: while getting labels for routing, routing without them: TestDefinitions can't be read when the suite is explained from a file
+`))
+	})
+
+	t.Run("requires configuration file if policies are enabled", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		err := runOfflineExplain(&bytes.Buffer{}, &config{SuiteFile: suiteFile, Policies: true}, nil)
+
+		g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("LogDispatchingPolicies can't be read without a cluster")))
+	})
+}
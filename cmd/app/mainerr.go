@@ -1,8 +1,6 @@
 package app
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -10,19 +8,20 @@ import (
 
 	logf "github.com/sirupsen/logrus"
 	slackGo "github.com/slack-go/slack"
-	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	pkgConfig "github.com/kyma-project/test-infra/test-log-collector/pkg/config"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/notifier"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
 	"github.com/kyma-project/test-infra/test-log-collector/pkg/slack"
-
-	pkgSlack "github.com/kyma-project/test-infra/test-log-collector/pkg/slack"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/spool"
 
 	"github.com/pkg/errors"
 	"github.com/vrischmann/envconfig"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	restclient "k8s.io/client-go/rest"
@@ -31,11 +30,58 @@ import (
 
 	"github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite"
 	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/resources/logdispatchingpolicy"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/resources/testdefinition"
 )
 
 type config struct {
-	SlackToken     string
-	ConfigLocation string
+	SlackToken     string        `envconfig:"optional"`
+	ConfigLocation string        `envconfig:"optional"`
+	Policies       bool          `envconfig:"default=false"`
+	Notifiers      []string      `envconfig:"default=slack"`
+	Mode           string        `envconfig:"default=job"`
+	ResyncPeriod   time.Duration `envconfig:"default=10m"`
+	SuiteName      string        `envconfig:"optional"`
+	SuiteSelector  string        `envconfig:"optional"`
+	CompletedAfter string        `envconfig:"optional"`
+	Containers     string        `envconfig:"default=primary"`
+	InitContainers bool          `envconfig:"default=false"`
+	IstioProxy     bool          `envconfig:"default=false"`
+	Parallelism    int           `envconfig:"default=5"`
+	PodTimeout     time.Duration `envconfig:"default=2m"`
+	MaxLogSize     string        `envconfig:"default=5Mi"`
+	DryRun         bool          `envconfig:"default=false"`
+	DryRunDir      string        `envconfig:"optional"`
+	SuiteFile      string        `envconfig:"optional"`
+	Platform       string        `envconfig:"default=unknown"`
+
+	ConfigReloadPeriod time.Duration `envconfig:"default=30s"`
+	MetricsAddress     string        `envconfig:"default=:8080"`
+}
+
+const (
+	modeJob        = "job"
+	modeController = "controller"
+	modeExplain    = "explain"
+)
+
+// labelGetter returns labels of the TestDefinition which are used for routing.
+type labelGetter interface {
+	Labels(namespace, name string) (map[string]string, error)
+}
+
+// reporter runs the collect-and-report pipeline for a single ClusterTestSuite.
+type reporter struct {
+	clientset kubernetes.Interface
+	testDefs  labelGetter
+	// state is replaced when the dispatching configuration is reloaded
+	state       *stateHolder
+	platform    hyperscaler.Platform
+	cluster     report.Cluster
+	containers  containerPolicy
+	parallelism int
+	podTimeout  time.Duration
+	maxLogSize  int64
 }
 
 func Mainerr() error {
@@ -44,16 +90,43 @@ func Mainerr() error {
 		return errors.Wrap(err, "while loading env config")
 	}
 
-	dispatchingConfig, err := pkgConfig.LoadDispatchingConfig(conf.ConfigLocation)
+	if conf.ConfigLocation == "" && !conf.Policies {
+		return errors.New("APP_CONFIG_LOCATION has to be set unless LogDispatchingPolicies are enabled with APP_POLICIES")
+	}
+
+	sel, err := newSuiteSelector(conf)
 	if err != nil {
-		return errors.Wrap(err, "while loading config for dispatching")
+		return errors.Wrap(err, "while creating ClusterTestSuite selector")
 	}
 
-	if err := dispatchingConfig.Validate(); err != nil {
-		return errors.Wrap(err, "while validating dispatching configuration")
+	if conf.Parallelism < 1 {
+		return fmt.Errorf("parallelism has to be at least 1, got %d", conf.Parallelism)
 	}
 
-	slackClient := slack.New(slackGo.New(conf.SlackToken))
+	maxLogSize, err := resource.ParseQuantity(conf.MaxLogSize)
+	if err != nil {
+		return errors.Wrapf(err, "while parsing max log size %s", conf.MaxLogSize)
+	}
+
+	containers, err := newContainerPolicy(conf)
+	if err != nil {
+		return errors.Wrap(err, "while creating container selection policy")
+	}
+
+	var resolver pkgConfig.ChannelResolver
+	// the Slack client is shared by every dispatching state, so that uploads stay paced across config reloads
+	var slackCli *slack.RetryingClient
+	if conf.SlackToken != "" {
+		api := slackGo.New(conf.SlackToken)
+		resolver = slack.NewChannelResolver(api)
+		slackCli = slack.NewRetryingClient(api)
+	} else {
+		logf.Warn("Slack token is not set, channels without channelID are identified by their names")
+	}
+
+	if conf.Mode == modeExplain && conf.SuiteFile != "" {
+		return runOfflineExplain(os.Stdout, conf, resolver)
+	}
 
 	client := getRestConfigOrDie()
 
@@ -67,6 +140,72 @@ func Mainerr() error {
 		return errors.Wrap(err, "while creating dynamicCli")
 	}
 
+	platform, err := hyperscaler.GetHyperScalerPlatform(clientset)
+	if err != nil {
+		return errors.Wrap(err, "while getting runtime's hyperscaler platform")
+	}
+
+	cluster := report.Cluster{}
+	if version, err := clientset.Discovery().ServerVersion(); err != nil {
+		logf.Warnf("while getting Kubernetes version, it won't be reported: %s", err)
+	} else {
+		cluster.KubernetesVersion = version.GitVersion
+	}
+
+	rep := reporter{
+		clientset:   clientset,
+		testDefs:    testdefinition.New(dynamicCli),
+		platform:    platform,
+		cluster:     cluster,
+		containers:  containers,
+		parallelism: conf.Parallelism,
+		podTimeout:  conf.PodTimeout,
+		maxLogSize:  maxLogSize.Value(),
+	}
+
+	policies := newPolicyClient(conf, logdispatchingpolicy.New(dynamicCli))
+	if conf.Mode == modeExplain {
+		// explaining doesn't send anything, so notifiers and their credentials aren't needed
+		dispatchingConfig, err := loadDispatching(conf, resolver, policies)
+		if err != nil {
+			return err
+		}
+		rep.state = newStateHolder(dispatchingState{dispatching: dispatchingConfig})
+		return runExplain(os.Stdout, conf.SuiteFile, dynamicCli, sel, rep)
+	}
+
+	threads := clustertestsuite.NewThreadStore(dynamicCli)
+	loadState := func() (dispatchingState, error) {
+		return newDispatchingState(conf, resolver, policies, slackCli, threads)
+	}
+
+	state, err := loadState()
+	if err != nil {
+		return err
+	}
+	setActiveConfigVersion("", state.version)
+	logf.Infof("dispatching configuration version %s is active", state.version)
+	holder := newStateHolder(state)
+	rep.state = holder
+
+	switch conf.Mode {
+	case modeJob:
+		return runJob(dynamicCli, sel, rep)
+	case modeController:
+		stopCh := signals.SetupSignalHandler()
+		if conf.MetricsAddress != "" {
+			serveMetrics(conf.MetricsAddress)
+		}
+		if conf.ConfigReloadPeriod > 0 {
+			go newReloader(loadState, holder).Run(conf.ConfigReloadPeriod, stopCh)
+		}
+		return newController(clustertestsuite.NewInformer(dynamicCli, conf.ResyncPeriod), newReportMarker(conf, dynamicCli), sel, rep.report).Run(stopCh)
+	default:
+		return fmt.Errorf("unknown mode %s, expected %s, %s or %s", conf.Mode, modeJob, modeController, modeExplain)
+	}
+}
+
+func runJob(dynamicCli dynamic.Interface, sel suiteSelector, rep reporter) error {
 	ctsCli := clustertestsuite.New(dynamicCli, 20*time.Second)
 
 	ctsList, err := ctsCli.List()
@@ -74,87 +213,272 @@ func Mainerr() error {
 		return errors.Wrapf(err, "while listing ClusterTestSuites")
 	}
 
-	newestCts, err := getNewestClusterTestSuite(ctsList) // todo: write tests here
+	selected, err := selectClusterTestSuites(ctsList, sel)
 	if err != nil {
-		return errors.Wrap(err, "while getting newest ClusterTestSuite")
+		return errors.Wrap(err, "while selecting ClusterTestSuites")
+	}
+
+	var errs []error
+	for _, cts := range selected {
+		logf.Infof("Reporting ClusterTestSuite %s", cts.Name)
+		if err := rep.report(cts); err != nil {
+			errs = append(errs, errors.Wrapf(err, "while reporting ClusterTestSuite %s", cts.Name))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r reporter) report(cts octopusTypes.ClusterTestSuite) error {
+	state := r.state.Load()
+	rep := report.Report{
+		Suite:    newSuite(cts),
+		Platform: string(r.platform),
+		Cluster:  r.cluster,
 	}
 
-	logf.Infof("Newest ClusterTestSuite name: %s", newestCts.Name)
+	for _, result := range cts.Status.Results {
+		testCase, err := r.newTestCase(state.dispatching, result)
+		if err != nil {
+			rep.Problems = append(rep.Problems, report.Problem{
+				Test:    result.Name,
+				Message: errors.Wrap(err, "while getting labels for routing, routing without them").Error(),
+			})
+		}
+
+		testConfigs, err := state.dispatching.GetConfigsForTestCaseWithFallback(testCase)
+		if err != nil {
+			rep.Problems = append(rep.Problems, report.Problem{
+				Test:    result.Name,
+				Message: errors.Wrapf(err, "while getting dispatching config for %s test suite", result.Name).Error(),
+			})
+			continue
+		}
 
-	logf.Info("Listing test pods")
+		routes, reason := newRoutes(testConfigs, result)
+		if len(routes) == 0 {
+			rep.Skipped = append(rep.Skipped, skip(result, reason))
+			continue
+		}
 
-	selector := labels.SelectorFromSet(map[string]string{
-		octopusTypes.LabelKeyCreatedByOctopus: "true",
-		octopusTypes.LabelKeySuiteName:        newestCts.Name,
-	})
+		rep.Results = append(rep.Results, report.TestResult{
+			Name:       result.Name,
+			Namespace:  result.Namespace,
+			Status:     string(result.Status),
+			Executions: newExecutions(result),
+			Routes:     routes,
+		})
+	}
 
-	pods, err := clientset.CoreV1().Pods("").List(metav1.ListOptions{
-		LabelSelector: selector.String(),
-	})
+	sp, err := spool.New(r.maxLogSize)
 	if err != nil {
-		return errors.Wrapf(err, "while listing pods by %s selector", selector)
+		return unsentError{errors.Wrap(err, "while creating spool for logs")}
 	}
+	defer func() {
+		if err := sp.Remove(); err != nil {
+			logf.Errorf("while removing spooled logs: %s", err)
+		}
+	}()
 
-	platform, err := hyperscaler.GetHyperScalerPlatform(clientset)
+	rep.Problems = append(rep.Problems, r.collectLogs(sp, rep.Results)...)
+
+	if err := state.sink.Notify(rep); err != nil {
+		return errors.Wrap(err, "while dispatching report")
+	}
+
+	return problemsError(rep.Problems)
+}
+
+// newTestCase returns routing information about the test. Labels are fetched only if the dispatching config uses them.
+func (r reporter) newTestCase(dispatching pkgConfig.Dispatching, result octopusTypes.TestResult) (pkgConfig.TestCase, error) {
+	tc := pkgConfig.TestCase{
+		Name:      result.Name,
+		Namespace: result.Namespace,
+		Platform:  string(r.platform),
+		Status:    string(result.Status),
+	}
+
+	if !dispatching.UsesLabels() {
+		return tc, nil
+	}
+
+	testLabels, err := r.testDefs.Labels(result.Namespace, result.Name)
 	if err != nil {
-		return errors.Wrap(err, "while getting runtime's hyperscaler platform")
+		return tc, err
 	}
+	tc.Labels = testLabels
+	return tc, nil
+}
 
-	var messages []pkgSlack.Message
+// newRoutes returns routes of channels which report the test. If there are none, it returns the reason why.
+func newRoutes(testConfigs []pkgConfig.LogsScrapingConfig, result octopusTypes.TestResult) ([]report.Route, string) {
+	var routes []report.Route
+	reason := fmt.Sprintf("no matching channel reports status %s", result.Status)
 
-	for _, pod := range pods.Items {
-		testName, ok := pod.Labels[octopusTypes.LabelKeyTestDefName]
-		if !ok {
-			return fmt.Errorf("there's no `%s` label on a pod %s in namespace %s", octopusTypes.LabelKeyTestDefName, pod.Name, pod.Namespace)
+	for _, testConfig := range testConfigs {
+		route, skipReason := newRoute(testConfig, result)
+		if skipReason == noExecutionsReason {
+			reason = skipReason
 		}
-
-		testConfig, err := dispatchingConfig.GetConfigByNameWithFallback(testName)
-		if err != nil {
-			return errors.Wrapf(err, "while getting dispatching config for %s test suite", testName)
+		if skipReason != "" {
+			continue
 		}
+		routes = append(routes, route)
+	}
+	return routes, reason
+}
+
+const noExecutionsReason = "it has no executions"
+
+// newRoute returns the route of the channel if it reports the test, otherwise the reason why it doesn't.
+func newRoute(testConfig pkgConfig.LogsScrapingConfig, result octopusTypes.TestResult) (report.Route, string) {
+	mode := testConfig.ReportModeFor(string(result.Status))
+	if mode == pkgConfig.ReportNone {
+		return report.Route{}, fmt.Sprintf("channel doesn't report status %s", result.Status)
+	}
+	if mode == pkgConfig.ReportLogs && len(result.Executions) == 0 {
+		return report.Route{}, noExecutionsReason
+	}
+
+	return report.Route{
+		ChannelName: testConfig.ChannelName,
+		ChannelID:   testConfig.ChannelID,
+		SummaryOnly: mode == pkgConfig.ReportSummary,
+	}, ""
+}
+
+func skip(result octopusTypes.TestResult, reason string) report.Skipped {
+	logf.Infof("skipping report of %s test suite because %s", result.Name, reason)
+	return report.Skipped{Test: result.Name, Status: string(result.Status), Reason: reason}
+}
+
+// unsentError is returned if reporting failed before anything has been sent, so that it can be safely retried.
+type unsentError struct {
+	err error
+}
+
+func (e unsentError) Error() string {
+	return e.err.Error()
+}
+
+// isUnsent tells whether nothing has been sent before the error occurred.
+func isUnsent(err error) bool {
+	_, ok := errors.Cause(err).(unsentError)
+	return ok
+}
 
-		status, err := extractTestStatus(testName, newestCts)
+// problemsError returns error listing every problem, so that the run is marked as failed
+// even though the report has been dispatched.
+func problemsError(problems []report.Problem) error {
+	var errs []error
+	for _, p := range problems {
+		errs = append(errs, errors.New(p.String()))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// newDispatchingState loads the dispatching configuration and creates notifiers which use its templates.
+func newDispatchingState(conf *config, resolver pkgConfig.ChannelResolver, policies policyClient, slackCli *slack.RetryingClient, threads slack.ThreadStore) (dispatchingState, error) {
+	dispatchingConfig, err := loadDispatching(conf, resolver, policies)
+	if err != nil {
+		return dispatchingState{}, err
+	}
+
+	templates, err := dispatchingConfig.Templates.Parse()
+	if err != nil {
+		return dispatchingState{}, errors.Wrap(err, "while parsing message templates")
+	}
+
+	sink, err := newNotifier(conf, templates, slackCli, threads)
+	if err != nil {
+		return dispatchingState{}, errors.Wrap(err, "while creating notifiers")
+	}
+
+	return dispatchingState{
+		version:     configVersion(dispatchingConfig),
+		dispatching: dispatchingConfig,
+		sink:        sink,
+	}, nil
+}
+
+// loadDispatching reads the dispatching configuration from the file and, if enabled, from LogDispatchingPolicies.
+func loadDispatching(conf *config, resolver pkgConfig.ChannelResolver, policies policyClient) (pkgConfig.Dispatching, error) {
+	dispatchingConfig := pkgConfig.Dispatching{}
+	if conf.ConfigLocation != "" {
+		var err error
+		dispatchingConfig, err = pkgConfig.LoadDispatchingConfig(conf.ConfigLocation)
 		if err != nil {
-			return errors.Wrapf(err, "while extracting test status from ClusterTestSuite for label %s", testName)
+			return pkgConfig.Dispatching{}, errors.Wrap(err, "while loading config for dispatching")
 		}
+	}
 
-		if status == octopusTypes.TestSucceeded && testConfig.OnlyReportFailure {
-			logf.Infof("skipping report of %s test suite because it has status %s", testName, string(status))
-			continue
+	if conf.Policies {
+		// the default route may come from a policy, so the file is checked as a whole only after merging
+		if err := dispatchingConfig.ValidateRoutes(); err != nil {
+			return pkgConfig.Dispatching{}, errors.Wrap(err, "while validating dispatching configuration")
 		}
 
-		container, err := getTestContainerName(pod)
+		var err error
+		dispatchingConfig, err = mergePolicies(policies, resolver, dispatchingConfig)
 		if err != nil {
-			return errors.Wrapf(err, "while extracting test container name from pod %s in namespace %s", pod.Name, pod.Namespace)
+			return pkgConfig.Dispatching{}, errors.Wrap(err, "while reading LogDispatchingPolicies")
 		}
-		logf.Info(fmt.Sprintf("Extracting logs from container %s from pod %s from namespace %s", container, pod.Name, pod.Namespace))
-		req := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
-			Container: container,
-		})
+	}
 
-		data, err := ConsumeRequest(req)
-		if err != nil {
-			return errors.Wrapf(err, "while reading request from container %s in pod %s in namespace %s", container, pod.Name, pod.Namespace)
-		}
-
-		messages = append(messages, pkgSlack.Message{
-			Data: string(data),
-			Attributes: pkgSlack.Attributes{
-				Name:             testName,
-				Status:           string(status),
-				ClusterTestSuite: newestCts.Name,
-				CompletionTime:   newestCts.Status.CompletionTime.String(),
-				Platform:         string(platform),
-			},
-			ChannelName: testConfig.ChannelName,
-			ChannelID:   testConfig.ChannelID,
-		})
+	if err := dispatchingConfig.Validate(); err != nil {
+		return pkgConfig.Dispatching{}, errors.Wrap(err, "while validating dispatching configuration")
+	}
+
+	dispatchingConfig, err := resolveChannels(resolver, dispatchingConfig)
+	if err != nil {
+		return pkgConfig.Dispatching{}, errors.Wrap(err, "while resolving Slack channels")
+	}
+	return dispatchingConfig, nil
+}
+
+// resolveChannels looks up IDs of channels by their names. Without a resolver, which is fine for notifiers
+// which don't talk to Slack, channels without channelID are identified by their names instead.
+func resolveChannels(resolver pkgConfig.ChannelResolver, dispatching pkgConfig.Dispatching) (pkgConfig.Dispatching, error) {
+	if resolver != nil {
+		return dispatching.ResolveChannelIDs(resolver)
+	}
+
+	unresolved := pkgConfig.Dispatching{Config: make([]pkgConfig.LogsScrapingConfig, len(dispatching.Config)), Templates: dispatching.Templates}
+	for i, route := range dispatching.Config {
+		unresolved.Config[i] = route
+		if route.ChannelID == "" {
+			unresolved.Config[i].ChannelID = route.ChannelName
+		}
+	}
+	return unresolved, nil
+}
+
+// newNotifier creates sinks of the report. slackCli is nil if the Slack token isn't set.
+func newNotifier(conf *config, templates *report.Templates, slackCli *slack.RetryingClient, threads slack.ThreadStore) (notifier.Notifier, error) {
+	var sinks notifier.Multi
+	for _, name := range conf.Notifiers {
+		switch name {
+		case "slack":
+			if conf.DryRun {
+				logf.Info("dry-run mode, reports won't be sent to Slack")
+				sinks = append(sinks, slack.NewDryRun(os.Stdout, conf.DryRunDir, templates))
+				continue
+			}
+			if slackCli == nil {
+				return nil, errors.New("slack notifier requires APP_SLACK_TOKEN to be set")
+			}
+			sinks = append(sinks, slack.New(slackCli, templates, threads))
+		case "log":
+			sinks = append(sinks, notifier.Log{})
+		default:
+			return nil, fmt.Errorf("unknown notifier %s", name)
+		}
 	}
 
-	if err := slackClient.UploadLogFiles(messages, newestCts.Name, newestCts.Status.CompletionTime.String(), string(platform)); err != nil {
-		return errors.Wrap(err, "while uploading files to slack thread")
+	if len(sinks) == 0 {
+		return nil, errors.New("no notifiers configured")
 	}
-	return nil
+	return sinks, nil
 }
 
 func getRestConfigOrDie() *restclient.Config {
@@ -173,51 +497,17 @@ func getRestConfigOrDie() *restclient.Config {
 	return client
 }
 
-func getTestContainerName(pod corev1.Pod) (string, error) {
-	names := []string{}
-	for _, cont := range pod.Spec.Containers {
-		if cont.Name != "istio-proxy" {
-			names = append(names, cont.Name)
-		}
-	}
-
-	if len(names) != 1 {
-		return "", fmt.Errorf("found more than 1 non-istio containers in pod %s in namespace %s", pod.Name, pod.Namespace)
-	}
-
-	return names[0], nil
-}
-
-// ConsumeRequest reads the data from request and writes into
-// the out writer. It buffers data from requests until the newline or io.EOF
-// occurs in the data, so it doesn't interleave logs sub-line
-// when running concurrently.
-//
-// A successful read returns err == nil, not err == io.EOF.
-// Because the function is defined to read from request until io.EOF, it does
-// not treat an io.EOF as an error to be reported.
-func ConsumeRequest(request restclient.ResponseWrapper) ([]byte, error) {
-	var b bytes.Buffer
+// ConsumeRequest copies the log stream of the request into the out writer.
+// The writer is responsible for limiting the size of the log.
+func ConsumeRequest(request restclient.ResponseWrapper, out io.Writer) error {
 	readCloser, err := request.Stream()
 	if err != nil {
-		return []byte{}, err
+		return err
 	}
 	defer readCloser.Close()
 
-	r := bufio.NewReader(readCloser)
-	for {
-		bytesln, err := r.ReadBytes('\n')
-		if _, err := b.Write(bytesln); err != nil {
-			return []byte{}, err
-		}
-
-		if err != nil {
-			if err != io.EOF {
-				return []byte{}, err
-			}
-			return b.Bytes(), nil
-		}
-	}
+	_, err = io.Copy(out, readCloser)
+	return err
 }
 
 func getNewestClusterTestSuite(ctsList octopusTypes.ClusterTestSuiteList) (octopusTypes.ClusterTestSuite, error) {
@@ -239,12 +529,3 @@ func getNewestClusterTestSuite(ctsList octopusTypes.ClusterTestSuiteList) (octop
 
 	return newest, nil
 }
-
-func extractTestStatus(defName string, cts octopusTypes.ClusterTestSuite) (octopusTypes.TestStatus, error) {
-	for _, result := range cts.Status.Results {
-		if defName == result.Name {
-			return result.Status, nil
-		}
-	}
-	return "", fmt.Errorf("couldn't find %s test in %s ClusterTestSuite status", defName, cts.Name)
-}
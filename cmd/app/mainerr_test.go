@@ -1,12 +1,16 @@
 package app
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	pkgConfig "github.com/kyma-project/test-infra/test-log-collector/pkg/config"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/hyperscaler"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
 	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
 )
 
@@ -128,3 +132,118 @@ func Test_getNewestClusterTestSuite(t *testing.T) {
 		})
 	}
 }
+
+func Test_newRoutes(t *testing.T) {
+	triage := pkgConfig.LogsScrapingConfig{ChannelName: "#triage", ChannelID: "id-1", Statuses: map[string]pkgConfig.ReportMode{"Failed": pkgConfig.ReportSummary}}
+	team := pkgConfig.LogsScrapingConfig{ChannelName: "#team", ChannelID: "id-2", OnlyReportFailure: true}
+	execution := octopusTypes.TestExecution{ID: "pod-1"}
+
+	tests := []struct {
+		name       string
+		configs    []pkgConfig.LogsScrapingConfig
+		result     octopusTypes.TestResult
+		wantRoutes []report.Route
+		wantReason string
+	}{
+		{
+			name:    "routes to every channel reporting the status",
+			configs: []pkgConfig.LogsScrapingConfig{team, triage},
+			result:  octopusTypes.TestResult{Status: octopusTypes.TestFailed, Executions: []octopusTypes.TestExecution{execution}},
+			wantRoutes: []report.Route{
+				{ChannelName: "#team", ChannelID: "id-2"},
+				{ChannelName: "#triage", ChannelID: "id-1", SummaryOnly: true},
+			},
+		},
+		{
+			name:    "reports summary of test without executions",
+			configs: []pkgConfig.LogsScrapingConfig{team, triage},
+			result:  octopusTypes.TestResult{Status: octopusTypes.TestFailed},
+			wantRoutes: []report.Route{
+				{ChannelName: "#triage", ChannelID: "id-1", SummaryOnly: true},
+			},
+		},
+		{
+			name:       "skips test which no channel reports",
+			configs:    []pkgConfig.LogsScrapingConfig{team, triage},
+			result:     octopusTypes.TestResult{Status: octopusTypes.TestSucceeded, Executions: []octopusTypes.TestExecution{execution}},
+			wantReason: "no matching channel reports status Succeeded",
+		},
+		{
+			name:       "skips test without executions",
+			configs:    []pkgConfig.LogsScrapingConfig{team},
+			result:     octopusTypes.TestResult{Status: octopusTypes.TestFailed},
+			wantReason: "it has no executions",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes, reason := newRoutes(tt.configs, tt.result)
+			if !reflect.DeepEqual(routes, tt.wantRoutes) {
+				t.Errorf("newRoutes() routes = %v, want %v", routes, tt.wantRoutes)
+			}
+			if len(routes) == 0 && reason != tt.wantReason {
+				t.Errorf("newRoutes() reason = %v, want %v", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+// fakeLabels returns labels of TestDefinitions by their namespace and name, or err for unknown ones.
+type fakeLabels map[string]map[string]string
+
+func (f fakeLabels) Labels(namespace, name string) (map[string]string, error) {
+	labels, ok := f[namespace+"/"+name]
+	if !ok {
+		return nil, errors.New("testdefinitions not found")
+	}
+	return labels, nil
+}
+
+func TestReporter_newTestCase(t *testing.T) {
+	rep := reporter{
+		testDefs: fakeLabels{"kyma-system/serverless": {"owner": "team-a"}},
+		platform: hyperscaler.Gke,
+	}
+	byLabels := pkgConfig.Dispatching{Config: []pkgConfig.LogsScrapingConfig{
+		{ChannelName: "#team-a", Labels: map[string]string{"owner": "team-a"}},
+	}}
+
+	tests := []struct {
+		name        string
+		dispatching pkgConfig.Dispatching
+		result      octopusTypes.TestResult
+		want        pkgConfig.TestCase
+		wantErr     bool
+	}{
+		{
+			name:        "doesn't fetch labels unless they're used for routing",
+			dispatching: pkgConfig.Dispatching{Config: []pkgConfig.LogsScrapingConfig{{ChannelName: "#default", TestCases: []string{"default"}}}},
+			result:      octopusTypes.TestResult{Name: "unknown", Namespace: "default"},
+			want:        pkgConfig.TestCase{Name: "unknown", Namespace: "default", Platform: "GKE"},
+		},
+		{
+			name:        "fetches labels of the TestDefinition",
+			dispatching: byLabels,
+			result:      octopusTypes.TestResult{Name: "serverless", Namespace: "kyma-system", Status: octopusTypes.TestFailed},
+			want:        pkgConfig.TestCase{Name: "serverless", Namespace: "kyma-system", Platform: "GKE", Status: "Failed", Labels: map[string]string{"owner": "team-a"}},
+		},
+		{
+			name:        "returns test case without labels if they can't be fetched",
+			dispatching: byLabels,
+			result:      octopusTypes.TestResult{Name: "unknown", Namespace: "default"},
+			want:        pkgConfig.TestCase{Name: "unknown", Namespace: "default", Platform: "GKE"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rep.newTestCase(tt.dispatching, tt.result)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newTestCase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newTestCase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	logf "github.com/sirupsen/logrus"
+)
+
+var (
+	metricsRegistry = prometheus.NewRegistry()
+
+	configInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "joby_dispatching_config_info",
+		Help: "Version of the active dispatching configuration, the value is always 1.",
+	}, []string{"version"})
+
+	configReloadFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "joby_dispatching_config_reload_failures_total",
+		Help: "Number of times a new dispatching configuration has been rejected.",
+	})
+)
+
+func init() {
+	metricsRegistry.MustRegister(configInfo, configReloadFailures)
+}
+
+// setActiveConfigVersion makes the metric point to the new configuration version only.
+func setActiveConfigVersion(previous, version string) {
+	if previous != "" {
+		configInfo.DeleteLabelValues(previous)
+	}
+	configInfo.WithLabelValues(version).Set(1)
+}
+
+// serveMetrics exposes metrics on given address in the background.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			logf.Errorf("while serving metrics on %s: %s", addr, err)
+		}
+	}()
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	logf "github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	pkgConfig "github.com/kyma-project/test-infra/test-log-collector/pkg/config"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/resources/logdispatchingpolicy"
+)
+
+// policyClient reads LogDispatchingPolicies and records results of their validation.
+type policyClient interface {
+	List() ([]logdispatchingpolicy.LogDispatchingPolicy, error)
+	UpdateStatus(policy logdispatchingpolicy.LogDispatchingPolicy, status logdispatchingpolicy.LogDispatchingPolicyStatus) error
+}
+
+// readOnlyPolicies lists LogDispatchingPolicies without writing their status,
+// for modes which mustn't change anything in the cluster, e.g. explain and dry run.
+type readOnlyPolicies struct {
+	policyClient
+}
+
+func (readOnlyPolicies) UpdateStatus(policy logdispatchingpolicy.LogDispatchingPolicy, status logdispatchingpolicy.LogDispatchingPolicyStatus) error {
+	return nil
+}
+
+// newPolicyClient returns client of LogDispatchingPolicies which writes their status only if reports are sent for real.
+func newPolicyClient(conf *config, policies policyClient) policyClient {
+	if conf.Mode == modeExplain || conf.DryRun {
+		return readOnlyPolicies{policies}
+	}
+	return policies
+}
+
+// mergePolicies appends routes of every valid LogDispatchingPolicy to the dispatching configuration.
+// Policies are validated one by one, together with the configuration and the policies accepted before them,
+// and the result is written to their status, so that a broken policy of one team doesn't affect routes of others.
+// Channel IDs of accepted routes are left for the caller to resolve, once the whole configuration is valid.
+func mergePolicies(client policyClient, resolver pkgConfig.ChannelResolver, dispatching pkgConfig.Dispatching) (pkgConfig.Dispatching, error) {
+	policies, err := client.List()
+	if err != nil {
+		return pkgConfig.Dispatching{}, err
+	}
+
+	merged := pkgConfig.Dispatching{
+		Config:    append([]pkgConfig.LogsScrapingConfig{}, dispatching.Config...),
+		Templates: dispatching.Templates,
+	}
+	for _, policy := range policies {
+		routes, err := policyRoutes(policy, resolver, merged)
+		status := logdispatchingpolicy.LogDispatchingPolicyStatus{
+			ObservedGeneration: policy.Generation,
+			Valid:              err == nil,
+			Problems:           problemMessages(err),
+		}
+
+		if err != nil {
+			logf.Warnf("LogDispatchingPolicy %s is invalid, its routes are ignored: %s", policy.Name, err)
+		} else {
+			merged.Config = append(merged.Config, routes...)
+		}
+
+		// the status is informational, routes are used regardless of whether it could be written
+		if err := client.UpdateStatus(policy, status); err != nil {
+			logf.Error(err)
+		}
+	}
+	return merged, nil
+}
+
+// policyRoutes returns routes of the policy if they're valid on their own and don't conflict with the accepted routes,
+// e.g. by adding another default route. Without a resolver, channels aren't checked against Slack.
+func policyRoutes(policy logdispatchingpolicy.LogDispatchingPolicy, resolver pkgConfig.ChannelResolver, accepted pkgConfig.Dispatching) ([]pkgConfig.LogsScrapingConfig, error) {
+	// routes are validated before they're marked with their source, which is obvious in the status of the policy
+	spec := pkgConfig.Dispatching{Config: policy.Spec.Routes}
+	if err := spec.ValidateRoutes(); err != nil {
+		return nil, err
+	}
+
+	if resolver != nil {
+		if _, err := spec.ResolveChannelIDs(resolver); err != nil {
+			return nil, err
+		}
+	}
+
+	routes := policy.Routes()
+	combined := pkgConfig.Dispatching{Config: append(append([]pkgConfig.LogsScrapingConfig{}, accepted.Config...), routes...)}
+	if err := combined.ValidateRoutes(); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+func problemMessages(err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	agg, ok := err.(utilerrors.Aggregate)
+	if !ok {
+		return []string{err.Error()}
+	}
+	var problems []string
+	for _, e := range agg.Errors() {
+		problems = append(problems, e.Error())
+	}
+	return problems
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pkgConfig "github.com/kyma-project/test-infra/test-log-collector/pkg/config"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/resources/logdispatchingpolicy"
+)
+
+type fakePolicies struct {
+	policies []logdispatchingpolicy.LogDispatchingPolicy
+	statuses map[string]logdispatchingpolicy.LogDispatchingPolicyStatus
+}
+
+func (f *fakePolicies) List() ([]logdispatchingpolicy.LogDispatchingPolicy, error) {
+	return f.policies, nil
+}
+
+func (f *fakePolicies) UpdateStatus(policy logdispatchingpolicy.LogDispatchingPolicy, status logdispatchingpolicy.LogDispatchingPolicyStatus) error {
+	f.statuses[policy.Name] = status
+	return nil
+}
+
+func TestMergePolicies(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	policy := func(name string, routes ...pkgConfig.LogsScrapingConfig) logdispatchingpolicy.LogDispatchingPolicy {
+		return logdispatchingpolicy.LogDispatchingPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 3},
+			Spec:       logdispatchingpolicy.LogDispatchingPolicySpec{Routes: routes},
+		}
+	}
+	client := &fakePolicies{
+		policies: []logdispatchingpolicy.LogDispatchingPolicy{
+			policy("team-a", pkgConfig.LogsScrapingConfig{ChannelName: "#team-a", Namespaces: []string{"serverless"}}),
+			policy("team-b", pkgConfig.LogsScrapingConfig{ChannelName: "team-b", TestCases: []string{"/rafter-(/"}}),
+			policy("second-default", pkgConfig.LogsScrapingConfig{ChannelName: "#other", TestCases: []string{"default"}}),
+			policy("same-channel", pkgConfig.LogsScrapingConfig{ChannelName: "#default", TestCases: []string{"default"}}),
+			policy("team-c", pkgConfig.LogsScrapingConfig{ChannelName: "#team-c", TestCases: []string{"rafter"}}),
+		},
+		statuses: map[string]logdispatchingpolicy.LogDispatchingPolicyStatus{},
+	}
+	file := pkgConfig.Dispatching{Config: []pkgConfig.LogsScrapingConfig{{ChannelName: "#default", TestCases: []string{"default"}}}}
+
+	merged, err := mergePolicies(client, nil, file)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(merged.Config).To(gomega.Equal([]pkgConfig.LogsScrapingConfig{
+		{ChannelName: "#default", TestCases: []string{"default"}},
+		{ChannelName: "#team-a", Namespaces: []string{"serverless"}, Source: "LogDispatchingPolicy team-a"},
+		{ChannelName: "#team-c", TestCases: []string{"rafter"}, Source: "LogDispatchingPolicy team-c"},
+	}))
+
+	g.Expect(client.statuses["team-a"]).To(gomega.Equal(logdispatchingpolicy.LogDispatchingPolicyStatus{ObservedGeneration: 3, Valid: true}))
+	g.Expect(client.statuses["team-b"].Valid).To(gomega.BeFalse())
+	g.Expect(client.statuses["team-b"].Problems).To(gomega.HaveLen(2))
+	g.Expect(client.statuses["team-b"].Problems[0]).To(gomega.Equal("route of channel team-b: channelName team-b should start with #"))
+
+	// policies conflicting with the file or accepted policies are rejected without affecting others
+	g.Expect(client.statuses["second-default"].Valid).To(gomega.BeFalse())
+	g.Expect(client.statuses["second-default"].Problems).To(gomega.HaveLen(1))
+	g.Expect(client.statuses["same-channel"].Valid).To(gomega.BeFalse())
+	g.Expect(client.statuses["same-channel"].Problems[0]).To(gomega.ContainSubstring("already routed to channel #default"))
+	g.Expect(client.statuses["team-c"].Valid).To(gomega.BeTrue())
+
+	g.Expect(merged.Validate()).To(gomega.Succeed())
+}
+
+func TestNewPolicyClient(t *testing.T) {
+	tests := []struct {
+		name         string
+		conf         *config
+		wantStatuses int
+	}{
+		{name: "writes status of policies", conf: &config{Mode: modeController}, wantStatuses: 1},
+		{name: "doesn't write status in the explain mode", conf: &config{Mode: modeExplain}},
+		{name: "doesn't write status in the dry-run mode", conf: &config{Mode: modeJob, DryRun: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			client := &fakePolicies{
+				policies: []logdispatchingpolicy.LogDispatchingPolicy{{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+					Spec: logdispatchingpolicy.LogDispatchingPolicySpec{Routes: []pkgConfig.LogsScrapingConfig{
+						{ChannelName: "#team-a", Namespaces: []string{"serverless"}},
+					}},
+				}},
+				statuses: map[string]logdispatchingpolicy.LogDispatchingPolicyStatus{},
+			}
+
+			merged, err := mergePolicies(newPolicyClient(tt.conf, client), nil, pkgConfig.Dispatching{})
+
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(merged.Config).To(gomega.HaveLen(1))
+			g.Expect(client.statuses).To(gomega.HaveLen(tt.wantStatuses))
+		})
+	}
+}
+
+func TestLoadDispatching_policiesWithoutSlackToken(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "policies")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "config.yaml")
+	g.Expect(ioutil.WriteFile(configFile, []byte(`apiVersion: joby.kyma-project.io/v1alpha1
+kind: DispatchingConfig
+routes:
+  - channelName: "#default"
+    testCases:
+      - default
+`), 0644)).To(gomega.Succeed())
+
+	client := &fakePolicies{
+		policies: []logdispatchingpolicy.LogDispatchingPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: logdispatchingpolicy.LogDispatchingPolicySpec{Routes: []pkgConfig.LogsScrapingConfig{
+				{ChannelName: "#team-a", Namespaces: []string{"serverless"}},
+			}},
+		}},
+		statuses: map[string]logdispatchingpolicy.LogDispatchingPolicyStatus{},
+	}
+
+	dispatching, err := loadDispatching(&config{ConfigLocation: configFile, Policies: true}, nil, client)
+
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(client.statuses["team-a"].Valid).To(gomega.BeTrue())
+	g.Expect(dispatching.Config).To(gomega.HaveLen(2))
+	g.Expect(dispatching.Config[1].ChannelID).To(gomega.Equal("#team-a"))
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	logf "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	pkgConfig "github.com/kyma-project/test-infra/test-log-collector/pkg/config"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/notifier"
+)
+
+// dispatchingState is everything derived from the dispatching configuration.
+type dispatchingState struct {
+	version     string
+	dispatching pkgConfig.Dispatching
+	sink        notifier.Notifier
+}
+
+// stateHolder keeps the active dispatching state. Every report uses the state which has been active when it started.
+type stateHolder struct {
+	mu    sync.RWMutex
+	state dispatchingState
+}
+
+func newStateHolder(state dispatchingState) *stateHolder {
+	return &stateHolder{state: state}
+}
+
+func (h *stateHolder) Load() dispatchingState {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.state
+}
+
+func (h *stateHolder) Store(state dispatchingState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.state = state
+}
+
+// configVersion identifies the effective configuration, so that a reload which doesn't change anything is a no-op.
+func configVersion(dispatching pkgConfig.Dispatching) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%#v", dispatching))))[:12]
+}
+
+// reloader periodically loads the dispatching configuration, e.g. after the ConfigMap mounted as the configuration
+// file or a LogDispatchingPolicy has changed. Invalid configuration is rejected and the last good one is kept.
+type reloader struct {
+	load   func() (dispatchingState, error)
+	holder *stateHolder
+}
+
+func newReloader(load func() (dispatchingState, error), holder *stateHolder) *reloader {
+	return &reloader{
+		load:   load,
+		holder: holder,
+	}
+}
+
+func (r *reloader) Run(period time.Duration, stopCh <-chan struct{}) {
+	wait.Until(r.reload, period, stopCh)
+}
+
+func (r *reloader) reload() {
+	current := r.holder.Load()
+
+	state, err := r.load()
+	if err != nil {
+		configReloadFailures.Inc()
+		logf.Errorf("rejecting new dispatching configuration, keeping version %s: %s", current.version, err)
+		return
+	}
+	if state.version == current.version {
+		return
+	}
+
+	r.holder.Store(state)
+	setActiveConfigVersion(current.version, state.version)
+	logf.Infof("dispatching configuration version %s is active, replacing version %s", state.version, current.version)
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	pkgConfig "github.com/kyma-project/test-infra/test-log-collector/pkg/config"
+)
+
+func TestReloader_reload(t *testing.T) {
+	newState := func(channel string) dispatchingState {
+		dispatching := pkgConfig.Dispatching{Config: []pkgConfig.LogsScrapingConfig{{ChannelName: channel, ChannelID: channel, TestCases: []string{"default"}}}}
+		return dispatchingState{version: configVersion(dispatching), dispatching: dispatching}
+	}
+	initial := newState("initial")
+
+	tests := []struct {
+		name   string
+		loaded dispatchingState
+		err    error
+		want   dispatchingState
+	}{
+		{
+			name:   "new version replaces the active state",
+			loaded: newState("changed"),
+			want:   newState("changed"),
+		},
+		{
+			name:   "invalid configuration is rejected",
+			loaded: newState("changed"),
+			err:    errors.New("invalid route"),
+			want:   initial,
+		},
+		{
+			name:   "same version keeps the active state",
+			loaded: dispatchingState{version: initial.version},
+			want:   initial,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			holder := newStateHolder(initial)
+			r := newReloader(func() (dispatchingState, error) {
+				return tt.loaded, tt.err
+			}, holder)
+
+			r.reload()
+
+			g.Expect(holder.Load()).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestConfigVersion(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	a := pkgConfig.Dispatching{Config: []pkgConfig.LogsScrapingConfig{{ChannelName: "a", TestCases: []string{"default"}}}}
+	b := pkgConfig.Dispatching{Config: []pkgConfig.LogsScrapingConfig{{ChannelName: "b", TestCases: []string{"default"}}}}
+
+	g.Expect(configVersion(a)).To(gomega.Equal(configVersion(a)))
+	g.Expect(configVersion(a)).NotTo(gomega.Equal(configVersion(b)))
+	g.Expect(configVersion(a)).To(gomega.HaveLen(12))
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+)
+
+// suiteSelector picks ClusterTestSuites which should be reported. Every criterion is optional,
+// the suite has to fulfil all criteria which are set.
+type suiteSelector struct {
+	name           string
+	labels         labels.Selector
+	completedAfter *time.Time
+}
+
+func newSuiteSelector(conf *config) (suiteSelector, error) {
+	sel := suiteSelector{name: conf.SuiteName}
+
+	if conf.SuiteSelector != "" {
+		parsed, err := labels.Parse(conf.SuiteSelector)
+		if err != nil {
+			return suiteSelector{}, errors.Wrapf(err, "while parsing label selector %s", conf.SuiteSelector)
+		}
+		sel.labels = parsed
+	}
+
+	if conf.CompletedAfter != "" {
+		parsed, err := time.Parse(time.RFC3339, conf.CompletedAfter)
+		if err != nil {
+			return suiteSelector{}, errors.Wrapf(err, "while parsing completion time %s", conf.CompletedAfter)
+		}
+		sel.completedAfter = &parsed
+	}
+
+	return sel, nil
+}
+
+// isEmpty tells whether no criterion has been set, in which case the newest ClusterTestSuite is reported.
+func (s suiteSelector) isEmpty() bool {
+	return s.name == "" && s.labels == nil && s.completedAfter == nil
+}
+
+func (s suiteSelector) matches(cts octopusTypes.ClusterTestSuite) bool {
+	if s.name != "" && cts.Name != s.name {
+		return false
+	}
+	if s.labels != nil && !s.labels.Matches(labels.Set(cts.Labels)) {
+		return false
+	}
+	if s.completedAfter != nil && (cts.Status.CompletionTime == nil || !cts.Status.CompletionTime.Time.After(*s.completedAfter)) {
+		return false
+	}
+	return true
+}
+
+// selectClusterTestSuites returns completed suites matching the selector, ordered by completion time.
+// Without any criteria it falls back to the newest completed suite.
+func selectClusterTestSuites(ctsList octopusTypes.ClusterTestSuiteList, sel suiteSelector) ([]octopusTypes.ClusterTestSuite, error) {
+	if sel.isEmpty() {
+		newest, err := getNewestClusterTestSuite(ctsList)
+		if err != nil {
+			return nil, errors.Wrap(err, "while getting newest ClusterTestSuite")
+		}
+		return []octopusTypes.ClusterTestSuite{newest}, nil
+	}
+
+	var selected []octopusTypes.ClusterTestSuite
+	for _, cts := range ctsList.Items {
+		if cts.Status.CompletionTime != nil && sel.matches(cts) {
+			selected = append(selected, cts)
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, errors.New("there's no completed ClusterTestSuite matching given criteria")
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Status.CompletionTime.Before(selected[j].Status.CompletionTime)
+	})
+
+	return selected, nil
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+)
+
+func Test_selectClusterTestSuites(t *testing.T) {
+	date2009 := metav1.NewTime(time.Date(2009, 11, 17, 20, 34, 58, 0, time.UTC))
+	date2019 := metav1.NewTime(time.Date(2019, 11, 17, 20, 34, 58, 0, time.UTC))
+	date2020 := metav1.NewTime(time.Date(2020, 11, 17, 20, 34, 58, 0, time.UTC))
+
+	ctsList := octopusTypes.ClusterTestSuiteList{Items: []octopusTypes.ClusterTestSuite{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly-2020", Labels: map[string]string{"type": "nightly"}},
+			Status:     octopusTypes.TestSuiteStatus{CompletionTime: &date2020},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly-2009", Labels: map[string]string{"type": "nightly"}},
+			Status:     octopusTypes.TestSuiteStatus{CompletionTime: &date2009},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "weekly-2019", Labels: map[string]string{"type": "weekly"}},
+			Status:     octopusTypes.TestSuiteStatus{CompletionTime: &date2019},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly-running", Labels: map[string]string{"type": "nightly"}},
+		},
+	}}
+
+	after2010 := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		conf      config
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name:      "returns newest suite without any criteria",
+			conf:      config{},
+			wantNames: []string{"nightly-2020"},
+		},
+		{
+			name:      "returns suite by name",
+			conf:      config{SuiteName: "nightly-2009"},
+			wantNames: []string{"nightly-2009"},
+		},
+		{
+			name:      "returns suites matching label selector ordered by completion time",
+			conf:      config{SuiteSelector: "type=nightly"},
+			wantNames: []string{"nightly-2009", "nightly-2020"},
+		},
+		{
+			name:      "returns suites completed after given time",
+			conf:      config{CompletedAfter: after2010.Format(time.RFC3339)},
+			wantNames: []string{"weekly-2019", "nightly-2020"},
+		},
+		{
+			name:      "combines criteria",
+			conf:      config{SuiteSelector: "type=nightly", CompletedAfter: after2010.Format(time.RFC3339)},
+			wantNames: []string{"nightly-2020"},
+		},
+		{
+			name:    "returns error if suite with given name is not completed",
+			conf:    config{SuiteName: "nightly-running"},
+			wantErr: true,
+		},
+		{
+			name:    "returns error if nothing matches",
+			conf:    config{SuiteSelector: "type=monthly"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			sel, err := newSuiteSelector(&tt.conf)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+
+			got, err := selectClusterTestSuites(ctsList, sel)
+			if tt.wantErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+
+			var names []string
+			for _, cts := range got {
+				names = append(names, cts.Name)
+			}
+			g.Expect(names).To(gomega.Equal(tt.wantNames))
+		})
+	}
+}
+
+func Test_newSuiteSelector(t *testing.T) {
+	t.Run("errors on invalid label selector", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		_, err := newSuiteSelector(&config{SuiteSelector: "type in nightly"})
+		g.Expect(err).To(gomega.HaveOccurred())
+	})
+	t.Run("errors on invalid time", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		_, err := newSuiteSelector(&config{CompletedAfter: "yesterday"})
+		g.Expect(err).To(gomega.HaveOccurred())
+	})
+}
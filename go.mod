@@ -6,10 +6,13 @@ require (
 	cloud.google.com/go v0.57.0 // indirect
 	github.com/onsi/gomega v1.10.1
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.0.0
 	github.com/sirupsen/logrus v1.4.2
 	github.com/slack-go/slack v0.6.5
 	github.com/vrischmann/envconfig v1.2.0
-	gopkg.in/yaml.v2 v2.3.0
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
+	gopkg.in/yaml.v2 v2.3.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 	k8s.io/api v0.17.7
 	k8s.io/apimachinery v0.17.7
 	k8s.io/client-go v0.17.7
@@ -3,38 +3,125 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/hyperscaler"
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
 )
 
+// LogsScrapingConfig is a single route. A test case is routed to the channel if it fulfils
+// every criterion which is set: testCases, namespaces, platforms, labels and labelSelector.
+// A test case is routed to every matching channel, unless one of them sets stop.
 type LogsScrapingConfig struct {
-	ChannelID         string   `yaml:"channelID"`
-	ChannelName       string   `yaml:"channelName"`
-	TestCases         []string `yaml:"testCases"`
-	OnlyReportFailure bool     `yaml:"onlyReportFailure"`
+	ChannelID     string            `yaml:"channelID" json:"channelID,omitempty"`
+	ChannelName   string            `yaml:"channelName" json:"channelName"`
+	TestCases     []string          `yaml:"testCases" json:"testCases,omitempty"`
+	Namespaces    []string          `yaml:"namespaces" json:"namespaces,omitempty"`
+	Platforms     []string          `yaml:"platforms" json:"platforms,omitempty"`
+	Labels        map[string]string `yaml:"labels" json:"labels,omitempty"`
+	LabelSelector string            `yaml:"labelSelector" json:"labelSelector,omitempty"`
+	// Statuses tells how tests with a particular status are reported, statuses which aren't listed are not reported.
+	// If it's empty, every status is reported with logs.
+	Statuses map[string]ReportMode `yaml:"statuses" json:"statuses,omitempty"`
+	// OnlyReportFailure is equivalent to reporting every status except Succeeded with logs.
+	// Deprecated: use Statuses instead.
+	OnlyReportFailure bool `yaml:"onlyReportFailure" json:"onlyReportFailure,omitempty"`
+	// Stop prevents routing the test case to any channel which matches it less precisely.
+	Stop bool `yaml:"stop" json:"stop,omitempty"`
+
+	// Line is the line of the configuration file where the route is defined, 0 if it's unknown
+	Line int `yaml:"-" json:"-"`
+	// Source names the object the route is defined in if it doesn't come from the configuration file,
+	// e.g. "LogDispatchingPolicy team-a"
+	Source string `yaml:"-" json:"-"`
+}
+
+// ReportMode tells how a test is reported.
+type ReportMode string
+
+const (
+	// ReportLogs attaches logs of every execution of the test
+	ReportLogs ReportMode = "logs"
+	// ReportSummary sends only a single line with the test name and its status
+	ReportSummary ReportMode = "summary"
+	// ReportNone doesn't report the test at all
+	ReportNone ReportMode = "none"
+)
+
+// reportableStatuses are test statuses which can be used in the statuses filter.
+var reportableStatuses = []string{"Failed", "Unknown", "Skipped", "Running", "Succeeded"}
+
+// ReportModeFor returns how a test with given status should be reported.
+func (c LogsScrapingConfig) ReportModeFor(status string) ReportMode {
+	if len(c.Statuses) > 0 {
+		if mode, ok := c.Statuses[status]; ok {
+			return mode
+		}
+		return ReportNone
+	}
+
+	if c.OnlyReportFailure && status == "Succeeded" {
+		return ReportNone
+	}
+	return ReportLogs
+}
+
+// ChannelResolver finds the ID of the channel with given name.
+type ChannelResolver interface {
+	ChannelID(name string) (string, error)
 }
 
 type Dispatching struct {
-	Config []LogsScrapingConfig
+	Config    []LogsScrapingConfig
+	Templates MessageTemplates
 }
 
-func LoadDispatchingConfig(path string) (Dispatching, error) {
-	dispatchingConfig := []LogsScrapingConfig{}
+// MessageTemplates are text/template strings rendered with report.MessageData. Empty templates are replaced with
+// defaults, see report.DefaultParentMessageTemplate and the following constants.
+type MessageTemplates struct {
+	ParentMessage string `yaml:"parentMessage"`
+	Comment       string `yaml:"comment"`
+	Filename      string `yaml:"filename"`
+	Title         string `yaml:"title"`
 
+	// Line is the line of the configuration file where templates are defined, 0 if it's unknown
+	Line int `yaml:"-"`
+}
+
+// Parse returns templates which render messages.
+func (t MessageTemplates) Parse() (*report.Templates, error) {
+	return report.NewTemplates(t.ParentMessage, t.Comment, t.Filename, t.Title)
+}
+
+// LoadDispatchingConfig reads the configuration from the file. If it can't be decoded,
+// the error lists every decoding problem along with problems found by Validate.
+func LoadDispatchingConfig(path string) (Dispatching, error) {
 	content, err := ioutil.ReadFile(path)
 	if err != nil {
 		return Dispatching{}, errors.Wrapf(err, "while reading configuration from %s", path)
 	}
 
-	if err := yaml.Unmarshal(content, &dispatchingConfig); err != nil {
-		return Dispatching{}, errors.Wrapf(err, "while unmarshalling configuration from %s", path)
+	dispatchingConfig, errs := parseDispatchingConfig(content)
+	if len(errs) > 0 {
+		if len(dispatchingConfig.Config) > 0 {
+			if err := dispatchingConfig.Validate(); err != nil {
+				if agg, ok := err.(utilerrors.Aggregate); ok {
+					errs = append(errs, agg.Errors()...)
+				} else {
+					errs = append(errs, err)
+				}
+			}
+		}
+		return Dispatching{}, errors.Wrapf(utilerrors.NewAggregate(errs), "while unmarshalling configuration from %s", path)
 	}
 
-	return Dispatching{
-		Config: dispatchingConfig,
-	}, nil
+	return dispatchingConfig, nil
 }
 
 func contains(slice []string, element string) bool {
@@ -47,14 +134,138 @@ func contains(slice []string, element string) bool {
 	return false
 }
 
+// GetConfigByName returns the config which matches the test case name most precisely.
+// If many configs match equally, the first one wins.
 func (d Dispatching) GetConfigByName(name string) (LogsScrapingConfig, error) {
+	return d.GetConfigForTestCase(TestCase{Name: name})
+}
+
+// GetConfigForTestCase returns the config which matches the test case most precisely.
+// If many configs match equally, the first one wins.
+func (d Dispatching) GetConfigForTestCase(tc TestCase) (LogsScrapingConfig, error) {
+	var best LogsScrapingConfig
+	var bestScore matchScore
+	matched := false
+
 	for _, conf := range d.Config {
-		if contains(conf.TestCases, name) {
-			return conf, nil
+		score, ok := conf.match(tc)
+		if ok && (!matched || score.betterThan(bestScore)) {
+			best, bestScore, matched = conf, score, true
 		}
 	}
 
-	return LogsScrapingConfig{}, fmt.Errorf("there's no configuration for %s test case", name)
+	if !matched {
+		return LogsScrapingConfig{}, fmt.Errorf("there's no configuration for %s test case", tc.Name)
+	}
+	return best, nil
+}
+
+// GetConfigsForTestCase returns every config which matches the test case, from the most precise one.
+// Configs matching less precisely than the first one which sets stop are left out.
+// If many configs match equally, they're returned in the order of definition.
+func (d Dispatching) GetConfigsForTestCase(tc TestCase) ([]LogsScrapingConfig, error) {
+	type scoredConfig struct {
+		config LogsScrapingConfig
+		score  matchScore
+	}
+
+	var matched []scoredConfig
+	for _, conf := range d.Config {
+		if score, ok := conf.match(tc); ok {
+			matched = append(matched, scoredConfig{config: conf, score: score})
+		}
+	}
+
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("there's no configuration for %s test case", tc.Name)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].score.betterThan(matched[j].score)
+	})
+
+	var configs []LogsScrapingConfig
+	for _, m := range matched {
+		configs = append(configs, m.config)
+		if m.config.Stop {
+			break
+		}
+	}
+	return configs, nil
+}
+
+// GetConfigsForTestCaseWithFallback returns configs for the test case or the config of the "default" test case.
+func (d Dispatching) GetConfigsForTestCaseWithFallback(tc TestCase) ([]LogsScrapingConfig, error) {
+	configs, _, err := d.MatchTestCase(tc)
+	return configs, err
+}
+
+// MatchTestCase returns configs for the test case or the config of the "default" test case,
+// and tells whether the latter has been used. The default config is also used if none of the matching configs
+// reports the status of the test case, e.g. if they're meant only for failures on a particular platform.
+func (d Dispatching) MatchTestCase(tc TestCase) ([]LogsScrapingConfig, bool, error) {
+	configs, err := d.GetConfigsForTestCase(tc)
+	if err == nil && reportsStatus(configs, tc.Status) {
+		return configs, false, nil
+	}
+
+	config, defaultErr := d.defaultConfig(tc)
+	if defaultErr != nil {
+		if err == nil {
+			// matching configs decide that the test case isn't reported
+			return configs, false, nil
+		}
+		return nil, false, defaultErr
+	}
+	return []LogsScrapingConfig{config}, true, nil
+}
+
+// reportsStatus tells whether any of the configs reports the status. Every status is assumed to be reported if it's unknown.
+func reportsStatus(configs []LogsScrapingConfig, status string) bool {
+	if status == "" {
+		return true
+	}
+	for _, config := range configs {
+		if config.ReportModeFor(status) != ReportNone {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultConfig returns the config of the "default" test case. Other criteria of the config are matched
+// against the test case, e.g. the default route may be set per platform.
+func (d Dispatching) defaultConfig(tc TestCase) (LogsScrapingConfig, error) {
+	// configs without testCases would match the default test case by other criteria alone
+	named := Dispatching{}
+	for _, config := range d.Config {
+		if len(config.TestCases) > 0 {
+			named.Config = append(named.Config, config)
+		}
+	}
+
+	tc.Name = "default"
+	return named.GetConfigForTestCase(tc)
+}
+
+// GetConfigForTestCaseWithFallback returns config for the test case or the config of the "default" test case.
+func (d Dispatching) GetConfigForTestCaseWithFallback(tc TestCase) (LogsScrapingConfig, error) {
+	config, err := d.GetConfigForTestCase(tc)
+	if err == nil {
+		return config, err
+	}
+
+	return d.defaultConfig(tc)
+}
+
+// UsesLabels tells whether any config matches on labels, so that they have to be fetched for test cases.
+func (d Dispatching) UsesLabels() bool {
+	for _, conf := range d.Config {
+		if len(conf.Labels) > 0 || conf.LabelSelector != "" {
+			return true
+		}
+	}
+	return false
 }
 
 func (d Dispatching) GetConfigByNameWithFallback(name string) (LogsScrapingConfig, error) {
@@ -66,11 +277,166 @@ func (d Dispatching) GetConfigByNameWithFallback(name string) (LogsScrapingConfi
 	return d.GetConfigByName("default")
 }
 
+// ResolveChannelIDs returns the configuration with IDs of every channel looked up by its name.
+// Routes which set channelID are checked against the ID of the named channel. Every problem is reported at once.
+func (d Dispatching) ResolveChannelIDs(resolver ChannelResolver) (Dispatching, error) {
+	var errs []error
+	resolved := Dispatching{Config: make([]LogsScrapingConfig, len(d.Config)), Templates: d.Templates}
+
+	for i, config := range d.Config {
+		resolved.Config[i] = config
+
+		id, err := resolver.ChannelID(config.ChannelName)
+		if err != nil {
+			errs = append(errs, config.errorf("while resolving ID of channel %s: %s", config.ChannelName, err))
+			continue
+		}
+		if config.ChannelID != "" && config.ChannelID != id {
+			errs = append(errs, config.errorf("channelID %s doesn't match ID %s of channel %s", config.ChannelID, id, config.ChannelName))
+			continue
+		}
+		resolved.Config[i].ChannelID = id
+	}
+
+	if len(errs) > 0 {
+		return Dispatching{}, utilerrors.NewAggregate(errs)
+	}
+	return resolved, nil
+}
+
+// Validate returns an error listing every problem found in the configuration.
 func (d Dispatching) Validate() error {
+	errs := d.routeErrors()
+	if d.defaultRoutes() == 0 {
+		errs = append(errs, errors.New("there's no route for the default test case, add it to testCases of the route which should receive tests matching no other route"))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidateRoutes is Validate for a part of the configuration, e.g. routes of a single LogDispatchingPolicy,
+// so it doesn't require the default route.
+func (d Dispatching) ValidateRoutes() error {
+	return utilerrors.NewAggregate(d.routeErrors())
+}
+
+func (d Dispatching) defaultRoutes() int {
+	n := 0
+	for _, config := range d.Config {
+		if contains(config.TestCases, "default") {
+			n++
+		}
+	}
+	return n
+}
+
+func (d Dispatching) routeErrors() []error {
+	var errs []error
+	// routedTestCases maps channel names to the test cases routed to them
+	routedTestCases := map[string]map[string]LogsScrapingConfig{}
+
 	for _, config := range d.Config {
 		if !strings.HasPrefix(config.ChannelName, "#") {
-			return fmt.Errorf("channelName %s should start with #", config.ChannelName)
+			errs = append(errs, config.errorf("channelName %s should start with #", config.ChannelName))
+		}
+		if config.ChannelID != "" && !channelIDRegexp.MatchString(config.ChannelID) {
+			errs = append(errs, config.errorf("channelID %q of channel %s should consist of an uppercase C, G or D followed by uppercase letters and digits", config.ChannelID, config.ChannelName))
+		}
+
+		if routedTestCases[config.ChannelName] == nil {
+			routedTestCases[config.ChannelName] = map[string]LogsScrapingConfig{}
+		}
+		listed := map[string]bool{}
+		for _, testCase := range config.TestCases {
+			if _, err := parseTestCasePattern(testCase); err != nil {
+				errs = append(errs, config.errorf("in testCases of channel %s: %s", config.ChannelName, err))
+			}
+			if listed[testCase] {
+				errs = append(errs, config.errorf("test case %s is listed more than once in testCases of channel %s", testCase, config.ChannelName))
+				continue
+			}
+			listed[testCase] = true
+
+			if other, ok := routedTestCases[config.ChannelName][testCase]; ok {
+				errs = append(errs, config.errorf("test case %s is already routed to channel %s by %s", testCase, config.ChannelName, other.Location()))
+				continue
+			}
+			routedTestCases[config.ChannelName][testCase] = config
+		}
+
+		for _, platform := range config.Platforms {
+			if !knownPlatform(platform) {
+				errs = append(errs, config.errorf("unknown platform %s in platforms of channel %s, expected one of %s", platform, config.ChannelName, strings.Join(knownPlatforms(), ", ")))
+			}
 		}
+		if config.LabelSelector != "" {
+			if _, err := labels.Parse(config.LabelSelector); err != nil {
+				errs = append(errs, config.errorf("in labelSelector of channel %s: %s", config.ChannelName, err))
+			}
+		}
+		if len(config.Statuses) > 0 && config.OnlyReportFailure {
+			errs = append(errs, config.errorf("statuses and onlyReportFailure of channel %s can't be used together", config.ChannelName))
+		}
+		for _, status := range sortedStatuses(config.Statuses) {
+			mode := config.Statuses[status]
+			if !contains(reportableStatuses, status) {
+				errs = append(errs, config.errorf("unknown status %s in statuses of channel %s, expected one of %s", status, config.ChannelName, strings.Join(reportableStatuses, ", ")))
+			}
+			if mode != ReportLogs && mode != ReportSummary && mode != ReportNone {
+				errs = append(errs, config.errorf("unknown report mode %s for status %s of channel %s, expected %s, %s or %s", mode, status, config.ChannelName, ReportLogs, ReportSummary, ReportNone))
+			}
+		}
+	}
+
+	if _, err := d.Templates.Parse(); err != nil {
+		if d.Templates.Line > 0 {
+			err = errors.Wrapf(err, "line %d", d.Templates.Line)
+		}
+		errs = append(errs, err)
+	}
+
+	if d.defaultRoutes() > 1 {
+		errs = append(errs, errors.New("the default test case is listed in more than one route"))
+	}
+	return errs
+}
+
+// channelIDRegexp matches IDs of public channels, private channels and direct messages.
+var channelIDRegexp = regexp.MustCompile(`^[CGD][A-Z0-9]{8,}$`)
+
+// errorf returns an error prefixed with the location of the route.
+func (c LogsScrapingConfig) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s: %s", c.Location(), fmt.Sprintf(format, args...))
+}
+
+// Location tells where the route has been defined: its line in the configuration file or the LogDispatchingPolicy.
+func (c LogsScrapingConfig) Location() string {
+	switch {
+	case c.Line > 0:
+		return fmt.Sprintf("line %d", c.Line)
+	case c.Source != "":
+		return fmt.Sprintf("%s, route of channel %s", c.Source, c.ChannelName)
+	default:
+		return fmt.Sprintf("route of channel %s", c.ChannelName)
+	}
+}
+
+func knownPlatforms() []string {
+	var platforms []string
+	for _, p := range hyperscaler.Platforms {
+		platforms = append(platforms, string(p))
+	}
+	return platforms
+}
+
+func knownPlatform(platform string) bool {
+	return contains(knownPlatforms(), platform)
+}
+
+func sortedStatuses(statuses map[string]ReportMode) []string {
+	var keys []string
+	for status := range statuses {
+		keys = append(keys, status)
 	}
-	return nil
+	sort.Strings(keys)
+	return keys
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"reflect"
@@ -104,6 +105,56 @@ func TestDispatching_GetConfigByName(t *testing.T) {
 			want:    LogsScrapingConfig{},
 			wantErr: true,
 		},
+		{
+			name: "return config matching glob",
+			fields: fields{Config: []LogsScrapingConfig{
+				{TestCases: []string{"rafter"}, ChannelName: "rafter"},
+				{TestCases: []string{"serverless-*"}, ChannelName: "serverless"},
+			}},
+			args:    args{name: "serverless-long"},
+			want:    LogsScrapingConfig{TestCases: []string{"serverless-*"}, ChannelName: "serverless"},
+			wantErr: false,
+		},
+		{
+			name: "return config matching regular expression",
+			fields: fields{Config: []LogsScrapingConfig{
+				{TestCases: []string{"rafter"}, ChannelName: "rafter"},
+				{TestCases: []string{"/-upgrade$/"}, ChannelName: "upgrade"},
+			}},
+			args:    args{name: "serverless-upgrade"},
+			want:    LogsScrapingConfig{TestCases: []string{"/-upgrade$/"}, ChannelName: "upgrade"},
+			wantErr: false,
+		},
+		{
+			name: "exact match beats pattern regardless of order",
+			fields: fields{Config: []LogsScrapingConfig{
+				{TestCases: []string{"serverless-*"}, ChannelName: "serverless"},
+				{TestCases: []string{"serverless-long"}, ChannelName: "serverless-long"},
+			}},
+			args:    args{name: "serverless-long"},
+			want:    LogsScrapingConfig{TestCases: []string{"serverless-long"}, ChannelName: "serverless-long"},
+			wantErr: false,
+		},
+		{
+			name: "more specific pattern wins",
+			fields: fields{Config: []LogsScrapingConfig{
+				{TestCases: []string{"*"}, ChannelName: "everything"},
+				{TestCases: []string{"serverless-*"}, ChannelName: "serverless"},
+				{TestCases: []string{"server*"}, ChannelName: "server"},
+			}},
+			args:    args{name: "serverless-long"},
+			want:    LogsScrapingConfig{TestCases: []string{"serverless-*"}, ChannelName: "serverless"},
+			wantErr: false,
+		},
+		{
+			name: "glob doesn't match partially",
+			fields: fields{Config: []LogsScrapingConfig{
+				{TestCases: []string{"serverless-?"}, ChannelName: "serverless"},
+			}},
+			args:    args{name: "serverless-long"},
+			want:    LogsScrapingConfig{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -123,22 +174,7 @@ func TestDispatching_GetConfigByName(t *testing.T) {
 }
 
 func TestLoadDispatchingConfig(t *testing.T) {
-	t.Run("properly reads configuration", func(t *testing.T) {
-		g := gomega.NewWithT(t)
-
-		// setup
-
-		content := []byte(`- channelName: "#work"
-  channelID: "chanID1"
-  onlyReportFailure: false
-  testCases:
-    - "rafter"
-- channelName: "#serverless-test"
-  channelID: "chanID2"
-  onlyReportFailure: true
-  testCases:
-    - serverless-long
-    - serverless`)
+	load := func(g *gomega.WithT, content string) (Dispatching, error) {
 		tmpfile, err := ioutil.TempFile("", "example")
 		g.Expect(err).To(gomega.Succeed())
 
@@ -146,32 +182,101 @@ func TestLoadDispatchingConfig(t *testing.T) {
 			g.Expect(os.Remove(tmpfile.Name())).To(gomega.Succeed()) // clean up
 		}()
 
-		_, err = tmpfile.Write(content)
+		_, err = tmpfile.Write([]byte(content))
 		g.Expect(err).ShouldNot(gomega.HaveOccurred())
+		g.Expect(tmpfile.Close()).Should(gomega.Succeed())
 
-		defer func() {
-			g.Expect(tmpfile.Close()).Should(gomega.Succeed())
-		}()
+		return LoadDispatchingConfig(tmpfile.Name())
+	}
 
-		// proper test phase
+	t.Run("properly reads configuration", func(t *testing.T) {
+		g := gomega.NewWithT(t)
 
-		conf, err := LoadDispatchingConfig(tmpfile.Name())
+		conf, err := load(g, `apiVersion: joby.kyma-project.io/v1alpha1
+kind: DispatchingConfig
+routes:
+  - channelName: "#work"
+    channelID: "C014YQ2R44E"
+    onlyReportFailure: false
+    testCases:
+      - "rafter"
+  - channelName: "#serverless-test"
+    channelID: "C0164BCSY75"
+    onlyReportFailure: true
+    testCases:
+      - serverless-long
+      - serverless`)
 		g.Expect(err).ToNot(gomega.HaveOccurred())
 		g.Expect(conf).To(gomega.Equal(Dispatching{Config: []LogsScrapingConfig{
 			{
-				ChannelID:         "chanID1",
+				ChannelID:         "C014YQ2R44E",
 				ChannelName:       "#work",
 				OnlyReportFailure: false,
 				TestCases:         []string{"rafter"},
+				Line:              4,
 			},
 			{
 				ChannelName:       "#serverless-test",
-				ChannelID:         "chanID2",
+				ChannelID:         "C0164BCSY75",
 				OnlyReportFailure: true,
 				TestCases:         []string{"serverless-long", "serverless"},
+				Line:              9,
 			},
 		}}))
 	})
+	t.Run("reads message templates", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		conf, err := load(g, `apiVersion: joby.kyma-project.io/v1alpha1
+kind: DispatchingConfig
+templates:
+  parentMessage: "{{.Suite.Name}} on {{.Platform}}"
+  title: "Logs of {{.Test.Name}}"
+routes:
+  - channelName: "#work"
+    testCases:
+      - default`)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(conf.Templates).To(gomega.Equal(MessageTemplates{
+			ParentMessage: "{{.Suite.Name}} on {{.Platform}}",
+			Title:         "Logs of {{.Test.Name}}",
+			Line:          4,
+		}))
+	})
+	t.Run("reports invalid template with its line", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		_, err := load(g, `apiVersion: joby.kyma-project.io/v1alpha1
+kind: DispatchingConfig
+templates:
+  comment: "{{.Test.Nmae}}"
+  filenme: "logs.txt"
+routes:
+  - channelName: "#work"
+    testCases:
+      - default`)
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(err.Error()).To(gomega.ContainSubstring("line 5: field filenme not found"))
+		g.Expect(err.Error()).To(gomega.ContainSubstring("line 4: "))
+		g.Expect(err.Error()).To(gomega.ContainSubstring("can't evaluate field Nmae"))
+	})
+	t.Run("properly reads list of routes", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		conf, err := load(g, `- channelName: "#work"
+  channelID: "C014YQ2R44E"
+  testCases:
+    - "rafter"
+- channelName: "#serverless-test"
+  channelID: "C0164BCSY75"
+  testCases:
+    - serverless`)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(conf).To(gomega.Equal(Dispatching{Config: []LogsScrapingConfig{
+			{ChannelID: "C014YQ2R44E", ChannelName: "#work", TestCases: []string{"rafter"}, Line: 1},
+			{ChannelID: "C0164BCSY75", ChannelName: "#serverless-test", TestCases: []string{"serverless"}, Line: 5},
+		}}))
+	})
 	t.Run("errors on wrong file path", func(t *testing.T) {
 		g := gomega.NewWithT(t)
 
@@ -182,80 +287,201 @@ func TestLoadDispatchingConfig(t *testing.T) {
 	t.Run("errors on misshaped config file", func(t *testing.T) {
 		g := gomega.NewWithT(t)
 
-		// setup
-
-		content := []byte(`- channelNamez: "#work"
+		conf, err := load(g, `- channelNamez: "#work"
 - channelName2: "#serverless-test"
   channelID2: "chanID2"
   testCases:
     mapInsteadOfSlice:
 		some: "data"
   `)
-		tmpfile, err := ioutil.TempFile("", "example")
-		g.Expect(err).To(gomega.Succeed())
-
-		defer func() {
-			g.Expect(os.Remove(tmpfile.Name())).To(gomega.Succeed()) // clean up
-		}()
-
-		_, err = tmpfile.Write(content)
-		g.Expect(err).ShouldNot(gomega.HaveOccurred())
-
-		defer func() {
-			g.Expect(tmpfile.Close()).Should(gomega.Succeed())
-		}()
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(conf).To(gomega.Equal(Dispatching{Config: nil}))
+	})
+	t.Run("errors on empty config file", func(t *testing.T) {
+		g := gomega.NewWithT(t)
 
-		// proper test phase
+		_, err := load(g, "")
+		g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("configuration is empty")))
+	})
+	t.Run("reports every problem with its line", func(t *testing.T) {
+		g := gomega.NewWithT(t)
 
-		conf, err := LoadDispatchingConfig(tmpfile.Name())
+		_, err := load(g, `apiVersion: joby.kyma-project.io/v2
+kind: DispatchingConfig
+routes:
+  - channelName: "#work"
+    channelID: "C014YQ2R44E"
+    onlyReportFaliure: true
+    testCases:
+      - default
+  - channelName: "serverless-test"
+    channelID: "C0164BCSY75"
+    testCases:
+      - serverless
+      - serverless
+  - channelName: "#work"
+    channelID: "C014YQ2R44E"
+    testCases:
+      - default`)
 		g.Expect(err).To(gomega.HaveOccurred())
-		g.Expect(conf).To(gomega.Equal(Dispatching{Config: nil}))
+		g.Expect(err.Error()).To(gomega.ContainSubstring(`line 1: apiVersion "joby.kyma-project.io/v2" is not supported`))
+		g.Expect(err.Error()).To(gomega.ContainSubstring("line 6: field onlyReportFaliure not found"))
+		g.Expect(err.Error()).To(gomega.ContainSubstring("line 9: channelName serverless-test should start with #"))
+		g.Expect(err.Error()).To(gomega.ContainSubstring("line 14: test case default is already routed to channel #work by line 4"))
+		g.Expect(err.Error()).To(gomega.ContainSubstring("the default test case is listed in more than one route"))
+		g.Expect(err.Error()).To(gomega.ContainSubstring("line 9: test case serverless is listed more than once in testCases of channel serverless-test"))
 	})
 }
 
 func TestDispatching_Validate(t *testing.T) {
-	type fields struct {
-		Config []LogsScrapingConfig
-	}
+	defaultRoute := LogsScrapingConfig{ChannelName: "#default", ChannelID: "C014YQ2R44E", TestCases: []string{"default"}}
+
 	tests := []struct {
 		name    string
-		fields  fields
+		config  []LogsScrapingConfig
 		wantErr bool
 	}{
 		{
 			name: "proper struct should pass validation",
-			fields: fields{Config: []LogsScrapingConfig{
-				{ChannelName: "#channel1"},
-				{ChannelName: "#channel2"},
-				{ChannelName: "#channel3"},
-			}},
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", TestCases: []string{"serverless"}},
+				{ChannelName: "#channel2", ChannelID: "G0164BCSY75", TestCases: []string{"serverless"}},
+				{ChannelName: "#channel3", ChannelID: "D0164BCSY75", Namespaces: []string{"kyma-system"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "struct without channelID should pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", TestCases: []string{"serverless"}},
+			},
 			wantErr: false,
 		},
 		{
 			name: "struct with channelName that do not start with '#' should not pass validation",
-			fields: fields{Config: []LogsScrapingConfig{
-				{ChannelName: "#channel1"},
-				{ChannelName: "channel2"},
-				{ChannelName: "#channel3"},
-			}},
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "channel2", ChannelID: "C0164BCSY75"},
+			},
 			wantErr: true,
 		},
 		{
-			name:    "no error on empty config slice",
-			fields:  fields{Config: []LogsScrapingConfig{}},
-			wantErr: false,
+			name: "struct with malformed channelID should not pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "chanID1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "struct with invalid glob should not pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", TestCases: []string{"serverless-[a-z"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "struct with invalid regular expression should not pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", TestCases: []string{"/serverless-(/"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "struct with invalid label selector should not pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", LabelSelector: "owner in team-a"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "struct with unknown platform should not pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", Platforms: []string{"EKS"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "struct with unknown status should not pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", Statuses: map[string]ReportMode{"Broken": ReportLogs}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "struct with unknown report mode should not pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", Statuses: map[string]ReportMode{"Failed": "everything"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "struct with both statuses and onlyReportFailure should not pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", Statuses: map[string]ReportMode{"Failed": ReportLogs}, OnlyReportFailure: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "struct routing test case to the same channel twice should not pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", TestCases: []string{"serverless"}},
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", TestCases: []string{"rafter", "serverless"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "struct without default route should not pass validation",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", TestCases: []string{"serverless"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "struct with many default routes should not pass validation",
+			config: []LogsScrapingConfig{
+				defaultRoute,
+				{ChannelName: "#channel1", ChannelID: "C0164BCSY75", TestCases: []string{"default"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "empty config slice should not pass validation",
+			config:  []LogsScrapingConfig{},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			d := Dispatching{
-				Config: tt.fields.Config,
+				Config: tt.config,
 			}
 			if err := d.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
+
+	t.Run("reports every problem", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		err := Dispatching{Config: []LogsScrapingConfig{
+			{ChannelName: "channel1", ChannelID: "C0164BCSY75", Line: 1},
+			{ChannelName: "#channel2", ChannelID: "chanID2", Line: 5},
+		}}.Validate()
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(err.Error()).To(gomega.Equal(`[line 1: channelName channel1 should start with #, line 5: channelID "chanID2" of channel #channel2 should consist of an uppercase C, G or D followed by uppercase letters and digits, there's no route for the default test case, add it to testCases of the route which should receive tests matching no other route]`))
+	})
 }
 
 func TestDispatching_GetConfigByNameWithFallback(t *testing.T) {
@@ -301,3 +527,351 @@ func TestDispatching_GetConfigByNameWithFallback(t *testing.T) {
 		})
 	}
 }
+
+func TestDispatching_GetConfigForTestCase(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   []LogsScrapingConfig
+		testCase TestCase
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "matches namespace",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#other", Namespaces: []string{"other"}},
+				{ChannelName: "#serverless", Namespaces: []string{"kyma-system", "serverless"}},
+			},
+			testCase: TestCase{Name: "function", Namespace: "serverless"},
+			want:     "#serverless",
+		},
+		{
+			name: "matches platform",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#gke-ops", Platforms: []string{"GKE"}},
+				{ChannelName: "#aks-ops", Platforms: []string{"AKS", "gardenerAzure"}},
+			},
+			testCase: TestCase{Name: "function", Platform: "gardenerAzure"},
+			want:     "#aks-ops",
+		},
+		{
+			name: "matches labels",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#team-a", Labels: map[string]string{"owner": "team-a"}},
+				{ChannelName: "#team-b", Labels: map[string]string{"owner": "team-b"}},
+			},
+			testCase: TestCase{Name: "function", Labels: map[string]string{"owner": "team-b", "tier": "1"}},
+			want:     "#team-b",
+		},
+		{
+			name: "matches label selector",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#teams", LabelSelector: "owner in (team-a, team-b),tier!=2"},
+			},
+			testCase: TestCase{Name: "function", Labels: map[string]string{"owner": "team-b", "tier": "1"}},
+			want:     "#teams",
+		},
+		{
+			name: "requires every criterion to match",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#team-a", Namespaces: []string{"serverless"}, Labels: map[string]string{"owner": "team-a"}},
+			},
+			testCase: TestCase{Name: "function", Namespace: "serverless", Labels: map[string]string{"owner": "team-b"}},
+			wantErr:  true,
+		},
+		{
+			name: "test name beats namespace and labels",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#team-a", Namespaces: []string{"serverless"}, Labels: map[string]string{"owner": "team-a"}},
+				{ChannelName: "#function", TestCases: []string{"function"}},
+			},
+			testCase: TestCase{Name: "function", Namespace: "serverless", Labels: map[string]string{"owner": "team-a"}},
+			want:     "#function",
+		},
+		{
+			name: "config with more criteria wins",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#serverless", Namespaces: []string{"serverless"}},
+				{ChannelName: "#team-a", Namespaces: []string{"serverless"}, Labels: map[string]string{"owner": "team-a"}},
+			},
+			testCase: TestCase{Name: "function", Namespace: "serverless", Labels: map[string]string{"owner": "team-a"}},
+			want:     "#team-a",
+		},
+		{
+			name: "config without criteria matches nothing",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#nothing"},
+			},
+			testCase: TestCase{Name: "function"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Dispatching{Config: tt.config}
+			got, err := d.GetConfigForTestCase(tt.testCase)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetConfigForTestCase() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.ChannelName != tt.want {
+				t.Errorf("GetConfigForTestCase() got = %v, want %v", got.ChannelName, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogsScrapingConfig_ReportModeFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		config LogsScrapingConfig
+		status string
+		want   ReportMode
+	}{
+		{
+			name:   "reports logs of every status by default",
+			config: LogsScrapingConfig{},
+			status: "Succeeded",
+			want:   ReportLogs,
+		},
+		{
+			name:   "onlyReportFailure doesn't report succeeded tests",
+			config: LogsScrapingConfig{OnlyReportFailure: true},
+			status: "Succeeded",
+			want:   ReportNone,
+		},
+		{
+			name:   "onlyReportFailure reports logs of failed tests",
+			config: LogsScrapingConfig{OnlyReportFailure: true},
+			status: "Failed",
+			want:   ReportLogs,
+		},
+		{
+			name:   "returns mode of listed status",
+			config: LogsScrapingConfig{Statuses: map[string]ReportMode{"Failed": ReportLogs, "Skipped": ReportSummary}},
+			status: "Skipped",
+			want:   ReportSummary,
+		},
+		{
+			name:   "doesn't report status which isn't listed",
+			config: LogsScrapingConfig{Statuses: map[string]ReportMode{"Failed": ReportLogs}},
+			status: "Succeeded",
+			want:   ReportNone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.ReportModeFor(tt.status); got != tt.want {
+				t.Errorf("ReportModeFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatching_GetConfigsForTestCase(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   []LogsScrapingConfig
+		testCase TestCase
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "returns every matching config from the most precise one",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#triage", TestCases: []string{"serverless-*"}},
+				{ChannelName: "#other", TestCases: []string{"monitoring"}},
+				{ChannelName: "#serverless", TestCases: []string{"serverless-long"}},
+				{ChannelName: "#team-a", Namespaces: []string{"serverless"}},
+			},
+			testCase: TestCase{Name: "serverless-long", Namespace: "serverless"},
+			want:     []string{"#serverless", "#triage", "#team-a"},
+		},
+		{
+			name: "keeps order of configs matching equally",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#first", TestCases: []string{"serverless"}},
+				{ChannelName: "#second", TestCases: []string{"serverless"}},
+			},
+			testCase: TestCase{Name: "serverless"},
+			want:     []string{"#first", "#second"},
+		},
+		{
+			name: "stops at config with stop",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#triage", TestCases: []string{"serverless-*"}},
+				{ChannelName: "#serverless", TestCases: []string{"serverless-long"}, Stop: true},
+			},
+			testCase: TestCase{Name: "serverless-long"},
+			want:     []string{"#serverless"},
+		},
+		{
+			name: "stop doesn't drop configs matching more precisely",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#triage", TestCases: []string{"serverless-*"}, Stop: true},
+				{ChannelName: "#serverless", TestCases: []string{"serverless-long"}},
+				{ChannelName: "#team-a", Namespaces: []string{"serverless"}},
+			},
+			testCase: TestCase{Name: "serverless-long", Namespace: "serverless"},
+			want:     []string{"#serverless", "#triage"},
+		},
+		{
+			name: "returns error if nothing matches",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#other", TestCases: []string{"monitoring"}},
+			},
+			testCase: TestCase{Name: "serverless-long"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			got, err := Dispatching{Config: tt.config}.GetConfigsForTestCase(tt.testCase)
+			if tt.wantErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+
+			var names []string
+			for _, conf := range got {
+				names = append(names, conf.ChannelName)
+			}
+			g.Expect(names).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestDispatching_MatchTestCase(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       []LogsScrapingConfig
+		testCase     TestCase
+		want         []string
+		wantFallback bool
+		wantErr      bool
+	}{
+		{
+			name: "returns matching configs",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#default", TestCases: []string{"default"}},
+				{ChannelName: "#serverless", TestCases: []string{"serverless-*"}},
+			},
+			testCase: TestCase{Name: "serverless-long"},
+			want:     []string{"#serverless"},
+		},
+		{
+			name: "falls back to the default test case",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#default", TestCases: []string{"default"}},
+				{ChannelName: "#serverless", TestCases: []string{"serverless-*"}},
+			},
+			testCase:     TestCase{Name: "monitoring"},
+			want:         []string{"#default"},
+			wantFallback: true,
+		},
+		{
+			name: "returns configs reporting the status",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#default", TestCases: []string{"default"}},
+				{ChannelName: "#aks-ops", Platforms: []string{"AKS"}, Statuses: map[string]ReportMode{"Failed": ReportLogs}},
+			},
+			testCase: TestCase{Name: "monitoring", Platform: "AKS", Status: "Failed"},
+			want:     []string{"#aks-ops"},
+		},
+		{
+			name: "falls back to the default test case if no matching config reports the status",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#default", TestCases: []string{"default"}},
+				{ChannelName: "#aks-ops", Platforms: []string{"AKS"}, Statuses: map[string]ReportMode{"Failed": ReportLogs}},
+			},
+			testCase:     TestCase{Name: "monitoring", Platform: "AKS", Status: "Succeeded"},
+			want:         []string{"#default"},
+			wantFallback: true,
+		},
+		{
+			name: "keeps configs not reporting the status without the default test case",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#aks-ops", Platforms: []string{"AKS"}, Statuses: map[string]ReportMode{"Failed": ReportLogs}},
+			},
+			testCase: TestCase{Name: "monitoring", Platform: "AKS", Status: "Succeeded"},
+			want:     []string{"#aks-ops"},
+		},
+		{
+			name: "matches other criteria of the default test case",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#gke-default", TestCases: []string{"default"}, Platforms: []string{"GKE"}},
+				{ChannelName: "#aks-default", TestCases: []string{"default"}, Platforms: []string{"AKS"}},
+			},
+			testCase:     TestCase{Name: "monitoring", Platform: "AKS"},
+			want:         []string{"#aks-default"},
+			wantFallback: true,
+		},
+		{
+			name: "returns error without the default test case",
+			config: []LogsScrapingConfig{
+				{ChannelName: "#serverless", TestCases: []string{"serverless-*"}},
+			},
+			testCase: TestCase{Name: "monitoring"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			got, fallback, err := Dispatching{Config: tt.config}.MatchTestCase(tt.testCase)
+			if tt.wantErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(fallback).To(gomega.Equal(tt.wantFallback))
+
+			var names []string
+			for _, conf := range got {
+				names = append(names, conf.ChannelName)
+			}
+			g.Expect(names).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+type fakeResolver map[string]string
+
+func (f fakeResolver) ChannelID(name string) (string, error) {
+	if id, ok := f[name]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("channel %s not found", name)
+}
+
+func TestDispatching_ResolveChannelIDs(t *testing.T) {
+	resolver := fakeResolver{"#work": "C014YQ2R44E", "#serverless": "C0164BCSY75"}
+
+	t.Run("fills in missing IDs", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		d, err := Dispatching{Config: []LogsScrapingConfig{
+			{ChannelName: "#work", TestCases: []string{"default"}},
+			{ChannelName: "#serverless", ChannelID: "C0164BCSY75", TestCases: []string{"serverless"}},
+		}}.ResolveChannelIDs(resolver)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(d.Config).To(gomega.Equal([]LogsScrapingConfig{
+			{ChannelName: "#work", ChannelID: "C014YQ2R44E", TestCases: []string{"default"}},
+			{ChannelName: "#serverless", ChannelID: "C0164BCSY75", TestCases: []string{"serverless"}},
+		}))
+	})
+
+	t.Run("reports every unknown channel and mismatching ID", func(t *testing.T) {
+		g := gomega.NewWithT(t)
+
+		_, err := Dispatching{Config: []LogsScrapingConfig{
+			{ChannelName: "#work", ChannelID: "C0164BCSY75", Line: 1},
+			{ChannelName: "#unknown", Line: 5},
+		}}.ResolveChannelIDs(resolver)
+		g.Expect(err).To(gomega.MatchError("[line 1: channelID C0164BCSY75 doesn't match ID C014YQ2R44E of channel #work, line 5: while resolving ID of channel #unknown: channel #unknown not found]"))
+	})
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// APIVersion is the version of the dispatching configuration document
+	APIVersion = "joby.kyma-project.io/v1alpha1"
+	// Kind is the kind of the dispatching configuration document
+	Kind = "DispatchingConfig"
+)
+
+// document is the versioned dispatching configuration, e.g.
+//
+//	apiVersion: joby.kyma-project.io/v1alpha1
+//	kind: DispatchingConfig
+//	routes:
+//	  - channelName: "#work"
+//	    ...
+//
+// A bare list of routes, which has been used before the configuration was versioned, is still accepted.
+type document struct {
+	APIVersion string               `yaml:"apiVersion"`
+	Kind       string               `yaml:"kind"`
+	Templates  MessageTemplates     `yaml:"templates"`
+	Routes     []LogsScrapingConfig `yaml:"routes"`
+}
+
+// parseDispatchingConfig strictly decodes the configuration, so that unknown fields are reported
+// instead of being ignored. It returns every decoding problem along with the line it occurred in.
+func parseDispatchingConfig(content []byte) (Dispatching, []error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return Dispatching{}, []error{err}
+	}
+	if len(root.Content) == 0 {
+		return Dispatching{}, []error{errors.New("configuration is empty")}
+	}
+
+	var errs []error
+	var routes []LogsScrapingConfig
+	var templates MessageTemplates
+	var routesNode *yaml.Node
+
+	node := root.Content[0]
+	switch node.Kind {
+	case yaml.SequenceNode:
+		routesNode = node
+		errs = append(errs, decodeStrict(content, &routes)...)
+	case yaml.MappingNode:
+		doc := document{}
+		errs = append(errs, decodeStrict(content, &doc)...)
+		if doc.APIVersion != APIVersion {
+			errs = append(errs, fmt.Errorf("line %d: apiVersion %q is not supported, expected %s", fieldLine(node, "apiVersion"), doc.APIVersion, APIVersion))
+		}
+		if doc.Kind != Kind {
+			errs = append(errs, fmt.Errorf("line %d: kind %q is not supported, expected %s", fieldLine(node, "kind"), doc.Kind, Kind))
+		}
+		routes = doc.Routes
+		routesNode = fieldValue(node, "routes")
+		templates = doc.Templates
+		if templatesNode := fieldValue(node, "templates"); templatesNode != nil {
+			templates.Line = templatesNode.Line
+		}
+	default:
+		return Dispatching{}, []error{fmt.Errorf("line %d: configuration should be a %s document or a list of routes", node.Line, Kind)}
+	}
+
+	if routesNode != nil && routesNode.Kind == yaml.SequenceNode {
+		for i := range routes {
+			if i < len(routesNode.Content) {
+				routes[i].Line = routesNode.Content[i].Line
+			}
+		}
+	}
+	return Dispatching{Config: routes, Templates: templates}, errs
+}
+
+// decodeStrict decodes content rejecting unknown fields. Every problem is returned, not only the first one.
+func decodeStrict(content []byte, out interface{}) []error {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+
+	err := dec.Decode(out)
+	if err == nil || err == io.EOF {
+		return nil
+	}
+
+	typeErr, ok := err.(*yaml.TypeError)
+	if !ok {
+		return []error{err}
+	}
+	var errs []error
+	for _, msg := range typeErr.Errors {
+		errs = append(errs, errors.New(msg))
+	}
+	return errs
+}
+
+// fieldValue returns the value of the key in the mapping node, or nil if there's no such key.
+func fieldValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// fieldLine returns the line of the key in the mapping node, or the line of the mapping itself if there's no such key.
+func fieldLine(mapping *yaml.Node, key string) int {
+	if value := fieldValue(mapping, key); value != nil {
+		return value.Line
+	}
+	return mapping.Line
+}
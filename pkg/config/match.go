@@ -0,0 +1,108 @@
+package config
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// TestCase describes a test which is being routed.
+type TestCase struct {
+	Name      string
+	Namespace string
+	// Labels of the TestDefinition and its pods
+	Labels map[string]string
+	// Platform is the hyperscaler the test has been run on
+	Platform string
+	// Status of the test, empty if it's unknown. The default route is used if no matching route reports it.
+	Status string
+}
+
+// matchScore tells how precisely a config matches a test case. Exact test name beats any pattern,
+// which beats configs without testCases. Then the pattern with more literal characters wins,
+// then the config with more criteria.
+type matchScore struct {
+	exact    bool
+	pattern  bool
+	literals int
+	criteria int
+}
+
+func (s matchScore) betterThan(other matchScore) bool {
+	if s.exact != other.exact {
+		return s.exact
+	}
+	if s.pattern != other.pattern {
+		return s.pattern
+	}
+	if s.literals != other.literals {
+		return s.literals > other.literals
+	}
+	return s.criteria > other.criteria
+}
+
+// match tells whether the test case fulfils every criterion of the config. Config without any criteria matches nothing.
+func (c LogsScrapingConfig) match(tc TestCase) (matchScore, bool) {
+	var score matchScore
+
+	if len(c.TestCases) > 0 {
+		nameScore, ok := c.matchTestCase(tc.Name)
+		if !ok {
+			return matchScore{}, false
+		}
+		score = nameScore
+	}
+
+	if len(c.Namespaces) > 0 {
+		if !contains(c.Namespaces, tc.Namespace) {
+			return matchScore{}, false
+		}
+		score.criteria++
+	}
+
+	if len(c.Platforms) > 0 {
+		if !contains(c.Platforms, tc.Platform) {
+			return matchScore{}, false
+		}
+		score.criteria++
+	}
+
+	if len(c.Labels) > 0 {
+		if !labels.SelectorFromSet(c.Labels).Matches(labels.Set(tc.Labels)) {
+			return matchScore{}, false
+		}
+		score.criteria += len(c.Labels)
+	}
+
+	if c.LabelSelector != "" {
+		selector, err := labels.Parse(c.LabelSelector)
+		if err != nil || !selector.Matches(labels.Set(tc.Labels)) {
+			return matchScore{}, false
+		}
+		score.criteria++
+	}
+
+	if !score.exact && !score.pattern && score.criteria == 0 {
+		return matchScore{}, false
+	}
+	return score, true
+}
+
+func (c LogsScrapingConfig) matchTestCase(name string) (matchScore, bool) {
+	if contains(c.TestCases, name) {
+		return matchScore{exact: true, literals: len(name)}, true
+	}
+
+	var best matchScore
+	matched := false
+	for _, raw := range c.TestCases {
+		pattern, err := parseTestCasePattern(raw)
+		if err != nil || pattern.exact || !pattern.match(name) {
+			continue
+		}
+
+		score := matchScore{pattern: true, literals: pattern.literals}
+		if !matched || score.betterThan(best) {
+			best, matched = score, true
+		}
+	}
+	return best, matched
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// testCasePattern is a single entry of testCases. Entries are matched as:
+//   - regular expressions, if they're wrapped in slashes, e.g. "/^serverless-.*$/"
+//   - globs, if they contain any of "*", "?" or "[", e.g. "*-upgrade"
+//   - exact test names otherwise
+type testCasePattern struct {
+	raw   string
+	exact bool
+	re    *regexp.Regexp
+	// literals is the number of non-wildcard characters, the more of them the more specific the pattern is
+	literals int
+}
+
+const globMetaChars = "*?["
+
+func parseTestCasePattern(raw string) (testCasePattern, error) {
+	if len(raw) > 2 && strings.HasPrefix(raw, "/") && strings.HasSuffix(raw, "/") {
+		expr := raw[1 : len(raw)-1]
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return testCasePattern{}, fmt.Errorf("invalid regular expression %s: %s", raw, err)
+		}
+		return testCasePattern{raw: raw, re: re, literals: countLiterals(expr, `\.+*?()|[]{}^$`)}, nil
+	}
+
+	if strings.ContainsAny(raw, globMetaChars) {
+		re, err := globToRegexp(raw)
+		if err != nil {
+			return testCasePattern{}, fmt.Errorf("invalid glob %s: %s", raw, err)
+		}
+		return testCasePattern{raw: raw, re: re, literals: countLiterals(raw, globMetaChars+"]!")}, nil
+	}
+
+	return testCasePattern{raw: raw, exact: true, literals: len(raw)}, nil
+}
+
+func (p testCasePattern) match(name string) bool {
+	if p.exact {
+		return p.raw == name
+	}
+	return p.re.MatchString(name)
+}
+
+// globToRegexp translates glob into anchored regular expression. "*" matches any sequence of characters,
+// "?" any single character and "[...]" a character class, which may be negated with "!".
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class at position %d", i)
+			}
+			class := glob[i+1 : i+1+end]
+			if class == "" || class == "!" {
+				return nil, fmt.Errorf("empty character class at position %d", i)
+			}
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+func countLiterals(s, metaChars string) int {
+	n := 0
+	for _, r := range s {
+		if !strings.ContainsRune(metaChars, r) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"testing"
+)
+
+func Test_testCasePattern_match(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		test    string
+		want    bool
+	}{
+		{name: "exact name", pattern: "rafter", test: "rafter", want: true},
+		{name: "exact name doesn't match prefix", pattern: "rafter", test: "rafter-upgrade", want: false},
+		{name: "exact name with regexp characters", pattern: "a.b", test: "axb", want: false},
+		{name: "glob with star", pattern: "*-upgrade", test: "serverless-upgrade", want: true},
+		{name: "glob with question mark", pattern: "test-?", test: "test-1", want: true},
+		{name: "glob with character class", pattern: "test-[0-9]", test: "test-7", want: true},
+		{name: "glob with negated character class", pattern: "test-[!0-9]", test: "test-7", want: false},
+		{name: "glob quotes regexp characters", pattern: "a.b*", test: "axb", want: false},
+		{name: "regular expression", pattern: "/^serverless(-long)?$/", test: "serverless-long", want: true},
+		{name: "regular expression is not anchored", pattern: "/less/", test: "serverless-long", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := parseTestCasePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("parseTestCasePattern() error = %v", err)
+			}
+			if got := p.match(tt.test); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseTestCasePattern(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		wantLiterals int
+		wantErr      bool
+	}{
+		{name: "exact name", pattern: "rafter", wantLiterals: 6},
+		{name: "glob", pattern: "serverless-*", wantLiterals: 11},
+		{name: "regular expression", pattern: "/^rafter.*$/", wantLiterals: 6},
+		{name: "unterminated character class", pattern: "test-[0-9", wantErr: true},
+		{name: "empty character class", pattern: "test-[]", wantErr: true},
+		{name: "invalid regular expression", pattern: "/test-(/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTestCasePattern(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseTestCasePattern() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.literals != tt.wantLiterals {
+				t.Errorf("parseTestCasePattern() literals = %v, want %v", got.literals, tt.wantLiterals)
+			}
+		})
+	}
+}
@@ -21,6 +21,9 @@ const (
 	Unknown         Platform = "unknown"
 )
 
+// Platforms lists every platform which can be detected.
+var Platforms = []Platform{Gke, Aks, GardenerAzure, GardenerGcp, UnknownGardener, Unknown}
+
 const (
 	shootCmNamespace = "kube-config"
 	shootCmName      = "shoot-info"
@@ -0,0 +1,87 @@
+package notifier
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	logf "github.com/sirupsen/logrus"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+)
+
+// Log writes reports using the application logger. Useful for clusters without access to any other sink.
+type Log struct{}
+
+func (Log) Notify(rep report.Report) error {
+	for _, result := range rep.Results {
+		if !result.NeedsLogs() {
+			logf.WithFields(logf.Fields{
+				"clusterTestSuite": rep.Suite.Name,
+				"test":             result.Name,
+				"status":           result.Status,
+				"channelNames":     channelNames(result.Routes),
+			}).Info("test summary")
+			continue
+		}
+
+		for _, exec := range result.Executions {
+			for _, container := range exec.Containers {
+				data, err := readLog(container.Log)
+				if err != nil {
+					return errors.Wrapf(err, "while reading logs of container %s in pod %s", container.Name, exec.ID)
+				}
+
+				logf.WithFields(logf.Fields{
+					"clusterTestSuite": rep.Suite.Name,
+					"completionTime":   rep.Suite.CompletionTime,
+					"platform":         rep.Platform,
+					"test":             result.Name,
+					"status":           result.Status,
+					"channelNames":     channelNames(result.Routes),
+					"execution":        exec.ID,
+					"attempt":          exec.Attempt,
+					"podPhase":         exec.PodPhase,
+					"decisive":         exec.Decisive,
+					"container":        container.Name,
+					"previous":         container.Previous,
+					"restartCount":     container.RestartCount,
+				}).Info(string(data))
+			}
+		}
+	}
+
+	for _, p := range rep.Problems {
+		logf.WithFields(logf.Fields{
+			"clusterTestSuite": rep.Suite.Name,
+			"test":             p.Test,
+			"execution":        p.Execution,
+			"container":        p.Container,
+		}).Warnf("collection problem: %s", p.Message)
+	}
+
+	for _, skipped := range rep.Skipped {
+		logf.WithFields(logf.Fields{
+			"clusterTestSuite": rep.Suite.Name,
+			"test":             skipped.Test,
+			"status":           skipped.Status,
+		}).Infof("skipped: %s", skipped.Reason)
+	}
+	return nil
+}
+
+func readLog(log report.Log) ([]byte, error) {
+	rc, err := log.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+func channelNames(routes []report.Route) []string {
+	var names []string
+	for _, route := range routes {
+		names = append(names, route.ChannelName)
+	}
+	return names
+}
@@ -0,0 +1,23 @@
+// Package notifier defines sinks to which reports are dispatched.
+package notifier
+
+import (
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+)
+
+// Notifier delivers a report to a particular sink, e.g. Slack.
+type Notifier interface {
+	Notify(rep report.Report) error
+}
+
+// Multi dispatches report to every notifier it consists of, stopping on the first error.
+type Multi []Notifier
+
+func (m Multi) Notify(rep report.Report) error {
+	for _, n := range m {
+		if err := n.Notify(rep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+// Package report contains the sink-agnostic model of a ClusterTestSuite report
+// which is passed to every notifier.
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Suite describes the ClusterTestSuite the report has been created for.
+type Suite struct {
+	Name           string
+	CompletionTime string
+	// StartTime is zero if the suite hasn't reported it
+	StartTime time.Time
+	// Conditions of the suite, e.g. Succeeded, Failed or Error
+	Conditions []Condition
+	// Tests summarizes every test of the suite, including the ones which aren't reported
+	Tests []TestSummary
+}
+
+// Condition is a condition of the ClusterTestSuite.
+type Condition struct {
+	Type string
+	// Status is True, False or Unknown
+	Status  string
+	Reason  string
+	Message string
+}
+
+// TestSummary describes a test of the suite without its logs.
+type TestSummary struct {
+	Name      string
+	Namespace string
+	Status    string
+	// Duration is the time from the start of the first execution to the end of the last one, zero if unknown
+	Duration time.Duration
+	// Retries is the number of executions after the first one
+	Retries int
+}
+
+// StatusCounts returns the number of tests of the suite by their status.
+func (s Suite) StatusCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, test := range s.Tests {
+		counts[test.Status]++
+	}
+	return counts
+}
+
+// Route tells the notifiers where a particular test result should be delivered.
+type Route struct {
+	ChannelName string
+	ChannelID   string
+	// SummaryOnly is set if the test should be reported to the channel with a single line, without logs
+	SummaryOnly bool
+}
+
+// Log points to logs stored in a file, so that they don't have to be kept in memory.
+type Log struct {
+	// Path is empty for logs which haven't been collected
+	Path string
+	// Size is the number of bytes in the file
+	Size int64
+	// Truncated is the number of bytes dropped from the middle of logs exceeding the size limit
+	Truncated int64
+}
+
+// Open returns reader of the logs. The caller is responsible for closing it.
+// The reader is seekable, so that uploads of the logs can be retried.
+func (l Log) Open() (io.ReadCloser, error) {
+	if l.Path == "" {
+		return emptyLog{strings.NewReader("")}, nil
+	}
+	return os.Open(l.Path)
+}
+
+// emptyLog is a seekable reader of logs which haven't been spooled, unlike the one of ioutil.NopCloser.
+type emptyLog struct {
+	*strings.Reader
+}
+
+func (emptyLog) Close() error {
+	return nil
+}
+
+// Termination describes how a container instance has terminated.
+type Termination struct {
+	ExitCode int32
+	// Reason is a brief reason set by the kubelet, e.g. OOMKilled
+	Reason  string
+	Message string
+}
+
+// ContainerLog holds logs of a single instance of a container of the testing pod.
+type ContainerLog struct {
+	Name string
+	// Init is set for init containers
+	Init bool
+	// Primary is set for the main container of the test
+	Primary bool
+	// Previous is set for logs of the instance which was running before the last restart
+	Previous     bool
+	RestartCount int32
+	// Termination is nil if the instance hasn't terminated
+	Termination *Termination
+	Log         Log
+}
+
+// Execution is a single run of a test, i.e. a single testing pod. Tests have many executions
+// in case of MaxRetries>0 or Count>1.
+type Execution struct {
+	ID string
+	// Attempt is 1-based number of the execution
+	Attempt  int
+	PodPhase string
+	Reason   string
+	Message  string
+	// Containers are ordered as their logs should be presented
+	Containers []ContainerLog
+	// Decisive is set on the execution which decided the final status of the test
+	Decisive bool
+}
+
+// TestResult is a single test from the ClusterTestSuite with logs of its executions.
+type TestResult struct {
+	Name       string
+	Namespace  string
+	Status     string
+	Executions []Execution
+	// Routes lists every channel which should receive the test
+	Routes []Route
+}
+
+// NeedsLogs tells whether any route reports logs of the test.
+func (r TestResult) NeedsLogs() bool {
+	for _, route := range r.Routes {
+		if !route.SummaryOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// Problem describes a failure which prevented joby from collecting logs of a test, or some part of them.
+type Problem struct {
+	Test string
+	// Execution and Container are empty if the problem concerns the whole test or execution
+	Execution string
+	Container string
+	Message   string
+	// Routes is empty if the problem couldn't be routed anywhere
+	Routes []Route
+}
+
+func (p Problem) String() string {
+	subject := fmt.Sprintf("test %s", p.Test)
+	if p.Execution != "" {
+		subject += fmt.Sprintf(", pod %s", p.Execution)
+	}
+	if p.Container != "" {
+		subject += fmt.Sprintf(", container %s", p.Container)
+	}
+	return fmt.Sprintf("%s: %s", subject, p.Message)
+}
+
+// Skipped is a test which is deliberately left out of the report.
+type Skipped struct {
+	Test   string
+	Status string
+	Reason string
+}
+
+// Cluster describes the cluster the ClusterTestSuite has been run on.
+type Cluster struct {
+	// KubernetesVersion is empty if it couldn't be detected
+	KubernetesVersion string
+}
+
+// Report is the whole outcome of a single ClusterTestSuite.
+type Report struct {
+	Suite    Suite
+	Platform string
+	Cluster  Cluster
+	Results  []TestResult
+	// Problems lists everything which couldn't be collected, results are reported regardless of them
+	Problems []Problem
+	Skipped  []Skipped
+}
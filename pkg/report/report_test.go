@@ -0,0 +1,50 @@
+package report
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestLog_Open(t *testing.T) {
+	dir, err := ioutil.TempDir("", "log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.log")
+	if err := ioutil.WriteFile(path, []byte("logs"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		log  Log
+		want string
+	}{
+		{name: "spooled log", log: Log{Path: path}, want: "logs"},
+		{name: "empty log", log: Log{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			r, err := tt.log.Open()
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			defer r.Close()
+
+			g.Expect(ioutil.ReadAll(r)).To(gomega.Equal([]byte(tt.want)))
+
+			// uploads are retried by reading the log again
+			seeker, ok := r.(io.Seeker)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(seeker.Seek(0, io.SeekStart)).To(gomega.BeZero())
+			g.Expect(ioutil.ReadAll(r)).To(gomega.Equal([]byte(tt.want)))
+		})
+	}
+}
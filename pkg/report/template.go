@@ -0,0 +1,179 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// MessageData is the data model of message templates:
+//   - Suite, Platform and Cluster describe the whole ClusterTestSuite run
+//   - Test is the reported test, nil in the parent message
+//   - Execution and Container describe the logs being uploaded, nil in the parent message and in test summaries
+type MessageData struct {
+	Suite     Suite
+	Platform  string
+	Cluster   Cluster
+	Test      *TestResult
+	Execution *Execution
+	Container *ContainerLog
+}
+
+const (
+	// DefaultParentMessageTemplate starts the thread of the ClusterTestSuite in every channel
+	DefaultParentMessageTemplate = `ClusterTestSuite {{.Suite.Name}}, completionTime {{.Suite.CompletionTime}}, platform {{.Platform}}`
+
+	// DefaultCommentTemplate describes uploaded logs, or the whole test if it's reported with a summary only
+	DefaultCommentTemplate = `Test {{.Test.Name}}, status: {{.Test.Status}}
+{{- with .Execution}}, attempt {{.Attempt}}/{{len $.Test.Executions}} (pod {{.ID}}), pod phase: {{.PodPhase}}
+{{- if .Reason}}, reason: {{.Reason}}{{end}}
+{{- if .Message}}, message: {{.Message}}{{end}}
+{{- if .Decisive}}, this attempt decided the final status{{end}}
+{{- end}}
+{{- with .Container}}
+{{- if .Init}}, init container: {{.Name}}{{else if .Name}}, container: {{.Name}}{{end}}
+{{- if .Previous}}, previous instance (restarts: {{.RestartCount}}){{end}}
+{{- with .Termination}}, exit code: {{.ExitCode}}{{if .Reason}} ({{.Reason}}){{end}}{{end}}
+{{- end}}`
+
+	// DefaultFilenameTemplate names uploaded log files, logs of other containers than the primary one are prefixed
+	// with the container name
+	DefaultFilenameTemplate = `{{with .Container}}{{if and .Name (not .Primary)}}{{.Name}}-{{end}}{{if .Previous}}previous-{{end}}{{end}}logs.txt`
+
+	// DefaultTitleTemplate is the title of uploaded log files
+	DefaultTitleTemplate = `Test logs`
+)
+
+// Templates render messages sent to channels.
+type Templates struct {
+	parentMessage *template.Template
+	comment       *template.Template
+	filename      *template.Template
+	title         *template.Template
+}
+
+// DefaultTemplates returns templates which are used unless they're overridden in the configuration.
+func DefaultTemplates() *Templates {
+	t, err := NewTemplates("", "", "", "")
+	if err != nil {
+		panic(fmt.Sprintf("default templates are invalid: %s", err))
+	}
+	return t
+}
+
+// NewTemplates parses templates and checks whether they can be rendered with the data model.
+// Empty templates are replaced with defaults.
+func NewTemplates(parentMessage, comment, filename, title string) (*Templates, error) {
+	t := &Templates{}
+	for _, tmpl := range []struct {
+		name     string
+		text     string
+		fallback string
+		dst      **template.Template
+	}{
+		{name: "parentMessage", text: parentMessage, fallback: DefaultParentMessageTemplate, dst: &t.parentMessage},
+		{name: "comment", text: comment, fallback: DefaultCommentTemplate, dst: &t.comment},
+		{name: "filename", text: filename, fallback: DefaultFilenameTemplate, dst: &t.filename},
+		{name: "title", text: title, fallback: DefaultTitleTemplate, dst: &t.title},
+	} {
+		text := tmpl.text
+		if text == "" {
+			text = tmpl.fallback
+		}
+		parsed, err := template.New(tmpl.name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while parsing %s template", tmpl.name)
+		}
+		*tmpl.dst = parsed
+	}
+
+	if err := t.check(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// check renders every template with sample data, so that templates referring to fields
+// which don't exist are rejected before anything is reported.
+func (t *Templates) check() error {
+	test := &TestResult{
+		Name:      "sample",
+		Namespace: "default",
+		Status:    "Failed",
+		Routes:    []Route{{ChannelName: "#sample", ChannelID: "C0000000000"}},
+	}
+	test.Executions = []Execution{{
+		ID:       "sample-0",
+		Attempt:  1,
+		PodPhase: "Failed",
+		Decisive: true,
+		Containers: []ContainerLog{{
+			Name:        "test",
+			Primary:     true,
+			Termination: &Termination{ExitCode: 1, Reason: "Error"},
+		}},
+	}}
+
+	parent := MessageData{
+		Suite:    Suite{Name: "sample", CompletionTime: "2020-01-01 00:00:00 +0000 UTC"},
+		Platform: "GKE",
+		Cluster:  Cluster{KubernetesVersion: "v1.16.0"},
+	}
+	summary := parent
+	summary.Test = test
+	logs := summary
+	logs.Execution = &test.Executions[0]
+	logs.Container = &test.Executions[0].Containers[0]
+
+	if _, err := t.ParentMessage(parent); err != nil {
+		return err
+	}
+	if _, err := t.Comment(summary); err != nil {
+		return errors.Wrap(err, "for a test reported with a summary only")
+	}
+	if _, err := t.Comment(logs); err != nil {
+		return err
+	}
+	if _, err := t.Filename(logs); err != nil {
+		return err
+	}
+	if _, err := t.Title(logs); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *Templates) ParentMessage(data MessageData) (string, error) {
+	return render(t.parentMessage, data)
+}
+
+func (t *Templates) Comment(data MessageData) (string, error) {
+	return render(t.comment, data)
+}
+
+// Filename returns the name of the uploaded log file, it's never empty.
+func (t *Templates) Filename(data MessageData) (string, error) {
+	name, err := render(t.filename, data)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(name) == "" {
+		return "", errors.New("filename template rendered an empty name")
+	}
+	return name, nil
+}
+
+func (t *Templates) Title(data MessageData) (string, error) {
+	return render(t.title, data)
+}
+
+func render(tmpl *template.Template, data MessageData) (string, error) {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", errors.Wrapf(err, "while rendering %s template", tmpl.Name())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,154 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestTemplates_Comment(t *testing.T) {
+	failed := func(executions ...Execution) *TestResult {
+		return &TestResult{Name: "test", Status: "Failed", Executions: executions}
+	}
+	pod1 := Execution{ID: "pod-1", Attempt: 1, PodPhase: "Failed"}
+	pod2 := Execution{ID: "pod-2", Attempt: 2, PodPhase: "Failed", Reason: "Error", Message: "exit code 1", Decisive: true}
+
+	tests := []struct {
+		name string
+		data MessageData
+		want string
+	}{
+		{
+			name: "without execution",
+			data: MessageData{Test: failed()},
+			want: "Test test, status: Failed",
+		},
+		{
+			name: "with decisive execution",
+			data: MessageData{Test: failed(pod1, pod2), Execution: &pod2},
+			want: "Test test, status: Failed, attempt 2/2 (pod pod-2), pod phase: Failed, reason: Error, message: exit code 1, this attempt decided the final status",
+		},
+		{
+			name: "with init container",
+			data: MessageData{Test: failed(pod1), Execution: &pod1, Container: &ContainerLog{Name: "setup", Init: true}},
+			want: "Test test, status: Failed, attempt 1/1 (pod pod-1), pod phase: Failed, init container: setup",
+		},
+		{
+			name: "with previous instance of restarted container",
+			data: MessageData{Test: failed(pod1), Execution: &pod1, Container: &ContainerLog{
+				Name: "test", Primary: true, Previous: true, RestartCount: 2, Termination: &Termination{ExitCode: 137, Reason: "OOMKilled"},
+			}},
+			want: "Test test, status: Failed, attempt 1/1 (pod pod-1), pod phase: Failed, container: test, previous instance (restarts: 2), exit code: 137 (OOMKilled)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(DefaultTemplates().Comment(tt.data)).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestTemplates_Filename(t *testing.T) {
+	tests := []struct {
+		name      string
+		container ContainerLog
+		want      string
+	}{
+		{
+			name:      "primary container",
+			container: ContainerLog{Name: "test", Primary: true},
+			want:      "logs.txt",
+		},
+		{
+			name:      "previous instance of primary container",
+			container: ContainerLog{Name: "test", Primary: true, Previous: true},
+			want:      "previous-logs.txt",
+		},
+		{
+			name:      "other container",
+			container: ContainerLog{Name: "db"},
+			want:      "db-logs.txt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+			g.Expect(DefaultTemplates().Filename(MessageData{Container: &tt.container})).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestNewTemplates(t *testing.T) {
+	t.Run("renders custom templates", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		tmpl, err := NewTemplates(
+			"{{.Suite.Name}} on {{.Platform}} ({{.Cluster.KubernetesVersion}})",
+			"{{.Test.Name}} in {{.Test.Namespace}}",
+			"{{.Test.Name}}-{{.Execution.Attempt}}.log",
+			"Logs of {{.Test.Name}}",
+		)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+
+		exec := Execution{Attempt: 2}
+		data := MessageData{
+			Suite:     Suite{Name: "cts"},
+			Platform:  "GKE",
+			Cluster:   Cluster{KubernetesVersion: "v1.16.0"},
+			Test:      &TestResult{Name: "test", Namespace: "default"},
+			Execution: &exec,
+		}
+		g.Expect(tmpl.ParentMessage(data)).To(gomega.Equal("cts on GKE (v1.16.0)"))
+		g.Expect(tmpl.Comment(data)).To(gomega.Equal("test in default"))
+		g.Expect(tmpl.Filename(data)).To(gomega.Equal("test-2.log"))
+		g.Expect(tmpl.Title(data)).To(gomega.Equal("Logs of test"))
+	})
+
+	t.Run("uses defaults for empty templates", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		tmpl, err := NewTemplates("", "", "", "")
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(tmpl.ParentMessage(MessageData{Suite: Suite{Name: "cts", CompletionTime: "now"}, Platform: "GKE"})).
+			To(gomega.Equal("ClusterTestSuite cts, completionTime now, platform GKE"))
+		g.Expect(tmpl.Title(MessageData{})).To(gomega.Equal("Test logs"))
+	})
+
+	tests := []struct {
+		name          string
+		parentMessage string
+		comment       string
+		filename      string
+		wantErr       string
+	}{
+		{
+			name:          "rejects template which doesn't parse",
+			parentMessage: "{{.Suite.Name",
+			wantErr:       "while parsing parentMessage template",
+		},
+		{
+			name:          "rejects unknown field",
+			parentMessage: "{{.Suite.Namespace}}",
+			wantErr:       "can't evaluate field Namespace",
+		},
+		{
+			name:    "rejects comment which can't be rendered for summaries",
+			comment: "{{.Execution.ID}}",
+			wantErr: "for a test reported with a summary only",
+		},
+		{
+			name:     "rejects empty filename",
+			filename: "{{if false}}logs.txt{{end}}",
+			wantErr:  "filename template rendered an empty name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewGomegaWithT(t)
+
+			_, err := NewTemplates(tt.parentMessage, tt.comment, tt.filename, "")
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(tt.wantErr)))
+		})
+	}
+}
@@ -1,11 +1,17 @@
 package clustertestsuite
 
 import (
+	"os"
 	"time"
 
+	"github.com/pkg/errors"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
 
 	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
 
@@ -40,6 +46,36 @@ func (cts ClusterTestSuite) List() (octopusTypes.ClusterTestSuiteList, error) {
 	return clusterTestSuites, nil
 }
 
+// NewInformer returns informer which watches ClusterTestSuites through the dynamic client.
+func NewInformer(dynamicCli dynamic.Interface, resync time.Duration) cache.SharedIndexInformer {
+	return dynamicinformer.NewFilteredDynamicInformer(dynamicCli, octopusTypes.SchemeGroupVersion.WithResource("clustertestsuites"), "", resync, cache.Indexers{}, nil).Informer()
+}
+
+// FromUnstructured converts object received from the dynamic client or informer into ClusterTestSuite.
+func FromUnstructured(u *unstructured.Unstructured) (octopusTypes.ClusterTestSuite, error) {
+	cts := octopusTypes.ClusterTestSuite{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &cts)
+	return cts, err
+}
+
+// LoadFromFile reads ClusterTestSuite from a YAML or JSON file, e.g. saved with kubectl get -o yaml.
+func LoadFromFile(path string) (octopusTypes.ClusterTestSuite, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return octopusTypes.ClusterTestSuite{}, errors.Wrapf(err, "while opening %s", path)
+	}
+	defer file.Close()
+
+	u := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(&u.Object); err != nil {
+		return octopusTypes.ClusterTestSuite{}, errors.Wrapf(err, "while decoding %s", path)
+	}
+	if u.GetKind() != "ClusterTestSuite" {
+		return octopusTypes.ClusterTestSuite{}, errors.Errorf("%s contains %s instead of ClusterTestSuite", path, u.GetKind())
+	}
+	return FromUnstructured(u)
+}
+
 func convertFromUnstructuredToClusterTestSuiteList(u *unstructured.Unstructured) (octopusTypes.ClusterTestSuiteList, error) {
 	cts := octopusTypes.ClusterTestSuiteList{}
 	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &cts)
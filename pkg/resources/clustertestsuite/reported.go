@@ -0,0 +1,51 @@
+package clustertestsuite
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+)
+
+// ReportedAnnotation holds the RFC3339 time at which the suite has been reported by the controller.
+const ReportedAnnotation = "joby.kyma-project.io/reported-at"
+
+// ReportMarker marks ClusterTestSuites as reported in their annotation, so that restarts of the controller
+// neither report a suite again nor miss suites completed in the meantime.
+type ReportMarker struct {
+	resCli dynamic.ResourceInterface
+}
+
+func NewReportMarker(dynamicCli dynamic.Interface) *ReportMarker {
+	return &ReportMarker{
+		resCli: dynamicCli.Resource(octopusTypes.SchemeGroupVersion.WithResource("clustertestsuites")),
+	}
+}
+
+// MarkReported sets the annotation of the suite to the current time.
+func (m ReportMarker) MarkReported(suite string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{ReportedAnnotation: time.Now().UTC().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := m.resCli.Patch(suite, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return errors.Wrapf(err, "while marking ClusterTestSuite %s as reported", suite)
+	}
+	return nil
+}
+
+// IsReported tells whether the suite has been marked as reported.
+func IsReported(cts octopusTypes.ClusterTestSuite) bool {
+	_, ok := cts.Annotations[ReportedAnnotation]
+	return ok
+}
@@ -0,0 +1,38 @@
+package clustertestsuite
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestReportMarker(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	suite := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "testing.kyma-project.io/v1alpha1",
+		"kind":       "ClusterTestSuite",
+		"metadata":   map[string]interface{}{"name": "testsuite-all"},
+	}}
+	marker := NewReportMarker(dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), suite))
+
+	u, err := marker.resCli.Get("testsuite-all", metav1.GetOptions{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	cts, err := FromUnstructured(u)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(IsReported(cts)).To(gomega.BeFalse())
+
+	g.Expect(marker.MarkReported("testsuite-all")).To(gomega.Succeed())
+
+	u, err = marker.resCli.Get("testsuite-all", metav1.GetOptions{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	cts, err = FromUnstructured(u)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(IsReported(cts)).To(gomega.BeTrue())
+
+	g.Expect(marker.MarkReported("other")).To(gomega.MatchError(gomega.ContainSubstring("while marking ClusterTestSuite other as reported")))
+}
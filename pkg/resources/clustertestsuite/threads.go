@@ -0,0 +1,87 @@
+package clustertestsuite
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+
+	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+)
+
+// ThreadsAnnotation holds timestamps of the Slack threads of the suite's report as a JSON object keyed by channel ID.
+const ThreadsAnnotation = "joby.kyma-project.io/slack-threads"
+
+// ThreadStore keeps Slack threads of ClusterTestSuites in their annotation, so that every report of a suite
+// goes to the same threads, regardless of the text of the parent message.
+type ThreadStore struct {
+	resCli dynamic.ResourceInterface
+}
+
+func NewThreadStore(dynamicCli dynamic.Interface) *ThreadStore {
+	return &ThreadStore{
+		resCli: dynamicCli.Resource(octopusTypes.SchemeGroupVersion.WithResource("clustertestsuites")),
+	}
+}
+
+// Threads returns timestamps of parent messages of the suite by channel ID.
+func (s ThreadStore) Threads(suite string) (map[string]string, error) {
+	u, err := s.resCli.Get(suite, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "while getting ClusterTestSuite %s", suite)
+	}
+	return threadsOf(u)
+}
+
+// SaveThread adds the thread in the channel to the annotation of the suite.
+func (s ThreadStore) SaveThread(suite, channelID, timestamp string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		u, err := s.resCli.Get(suite, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "while getting ClusterTestSuite %s", suite)
+		}
+
+		threads, err := threadsOf(u)
+		if err != nil {
+			return err
+		}
+		if threads[channelID] == timestamp {
+			return nil
+		}
+		threads[channelID] = timestamp
+
+		value, err := json.Marshal(threads)
+		if err != nil {
+			return err
+		}
+		// resourceVersion makes the patch fail on conflict, so that concurrent runs don't drop each other's threads
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"resourceVersion": u.GetResourceVersion(),
+				"annotations":     map[string]string{ThreadsAnnotation: string(value)},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = s.resCli.Patch(suite, types.MergePatchType, patch, metav1.PatchOptions{})
+		return err
+	})
+}
+
+func threadsOf(u *unstructured.Unstructured) (map[string]string, error) {
+	threads := make(map[string]string)
+	value, ok := u.GetAnnotations()[ThreadsAnnotation]
+	if !ok {
+		return threads, nil
+	}
+	if err := json.Unmarshal([]byte(value), &threads); err != nil {
+		return nil, errors.Wrapf(err, "while decoding %s annotation of ClusterTestSuite %s", ThreadsAnnotation, u.GetName())
+	}
+	return threads, nil
+}
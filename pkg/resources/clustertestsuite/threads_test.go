@@ -0,0 +1,34 @@
+package clustertestsuite
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestThreadStore(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	suite := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "testing.kyma-project.io/v1alpha1",
+		"kind":       "ClusterTestSuite",
+		"metadata":   map[string]interface{}{"name": "testsuite-all"},
+	}}
+	store := NewThreadStore(dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), suite))
+
+	g.Expect(store.Threads("testsuite-all")).To(gomega.BeEmpty())
+
+	g.Expect(store.SaveThread("testsuite-all", "C0164BCSY75", "1594728000.000100")).To(gomega.Succeed())
+	g.Expect(store.SaveThread("testsuite-all", "C014YQ2R44E", "1594728000.000200")).To(gomega.Succeed())
+
+	g.Expect(store.Threads("testsuite-all")).To(gomega.Equal(map[string]string{
+		"C0164BCSY75": "1594728000.000100",
+		"C014YQ2R44E": "1594728000.000200",
+	}))
+
+	_, err := store.Threads("other")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
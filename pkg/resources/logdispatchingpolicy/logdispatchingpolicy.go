@@ -0,0 +1,105 @@
+// Package logdispatchingpolicy reads LogDispatchingPolicies, cluster-scoped objects which let teams own
+// their routes instead of editing the dispatching configuration file.
+package logdispatchingpolicy
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/config"
+)
+
+// GroupVersionResource identifies LogDispatchingPolicies in the API.
+var GroupVersionResource = schema.GroupVersionResource{
+	Group:    "joby.kyma-project.io",
+	Version:  "v1alpha1",
+	Resource: "logdispatchingpolicies",
+}
+
+type LogDispatchingPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LogDispatchingPolicySpec   `json:"spec"`
+	Status LogDispatchingPolicyStatus `json:"status,omitempty"`
+}
+
+type LogDispatchingPolicySpec struct {
+	Routes []config.LogsScrapingConfig `json:"routes"`
+}
+
+// LogDispatchingPolicyStatus is written by joby after validating the policy. Routes of invalid policies are ignored.
+type LogDispatchingPolicyStatus struct {
+	// ObservedGeneration is the generation of the spec which has been validated
+	ObservedGeneration int64    `json:"observedGeneration,omitempty"`
+	Valid              bool     `json:"valid"`
+	Problems           []string `json:"problems,omitempty"`
+}
+
+type Client struct {
+	resCli dynamic.NamespaceableResourceInterface
+}
+
+func New(dynamicCli dynamic.Interface) *Client {
+	return &Client{
+		resCli: dynamicCli.Resource(GroupVersionResource),
+	}
+}
+
+func (c Client) List() ([]LogDispatchingPolicy, error) {
+	ul, err := c.resCli.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "while listing LogDispatchingPolicies")
+	}
+
+	var policies []LogDispatchingPolicy
+	for i := range ul.Items {
+		policy, err := FromUnstructured(&ul.Items[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "while converting LogDispatchingPolicy %s", ul.Items[i].GetName())
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// UpdateStatus writes the status of the policy, unless it's already up to date.
+func (c Client) UpdateStatus(policy LogDispatchingPolicy, status LogDispatchingPolicyStatus) error {
+	if reflect.DeepEqual(policy.Status, status) {
+		return nil
+	}
+	policy.Status = status
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&policy)
+	if err != nil {
+		return errors.Wrapf(err, "while converting LogDispatchingPolicy %s", policy.Name)
+	}
+
+	if _, err := c.resCli.UpdateStatus(&unstructured.Unstructured{Object: obj}, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "while updating status of LogDispatchingPolicy %s", policy.Name)
+	}
+	return nil
+}
+
+// FromUnstructured converts object received from the dynamic client into LogDispatchingPolicy.
+func FromUnstructured(u *unstructured.Unstructured) (LogDispatchingPolicy, error) {
+	policy := LogDispatchingPolicy{}
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &policy)
+	return policy, err
+}
+
+// Routes returns routes of the policy, marked with the policy they come from.
+func (p LogDispatchingPolicy) Routes() []config.LogsScrapingConfig {
+	var routes []config.LogsScrapingConfig
+	for _, route := range p.Spec.Routes {
+		route.Source = "LogDispatchingPolicy " + p.Name
+		routes = append(routes, route)
+	}
+	return routes
+}
@@ -0,0 +1,53 @@
+package logdispatchingpolicy
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/config"
+)
+
+func TestClient(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "joby.kyma-project.io/v1alpha1",
+		"kind":       "LogDispatchingPolicy",
+		"metadata":   map[string]interface{}{"name": "team-a", "generation": int64(2)},
+		"spec": map[string]interface{}{
+			"routes": []interface{}{
+				map[string]interface{}{
+					"channelName": "#team-a",
+					"namespaces":  []interface{}{"serverless"},
+					"statuses":    map[string]interface{}{"Failed": "logs"},
+				},
+			},
+		},
+	}}
+	client := New(dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), policy))
+
+	policies, err := client.List()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(policies).To(gomega.HaveLen(1))
+	g.Expect(policies[0].Name).To(gomega.Equal("team-a"))
+	g.Expect(policies[0].Spec.Routes).To(gomega.Equal([]config.LogsScrapingConfig{{
+		ChannelName: "#team-a",
+		Namespaces:  []string{"serverless"},
+		Statuses:    map[string]config.ReportMode{"Failed": config.ReportLogs},
+	}}))
+	g.Expect(policies[0].Routes()[0].Source).To(gomega.Equal("LogDispatchingPolicy team-a"))
+
+	status := LogDispatchingPolicyStatus{ObservedGeneration: 2, Problems: []string{"channelName team-a should start with #"}}
+	g.Expect(client.UpdateStatus(policies[0], status)).To(gomega.Succeed())
+
+	updated, err := client.resCli.Get("team-a", metav1.GetOptions{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	got, err := FromUnstructured(updated)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(got.Status).To(gomega.Equal(status))
+}
@@ -0,0 +1,43 @@
+package testdefinition
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	octopusTypes "github.com/kyma-project/test-infra/test-log-collector/pkg/resources/clustertestsuite/types"
+)
+
+type TestDefinition struct {
+	resCli dynamic.NamespaceableResourceInterface
+}
+
+func New(dynamicCli dynamic.Interface) *TestDefinition {
+	return &TestDefinition{
+		resCli: dynamicCli.Resource(octopusTypes.SchemeGroupVersion.WithResource("testdefinitions")),
+	}
+}
+
+// Labels returns labels of the TestDefinition merged with labels of its pod template.
+// Labels of the TestDefinition itself take precedence.
+func (td TestDefinition) Labels(namespace, name string) (map[string]string, error) {
+	u, err := td.resCli.Namespace(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "while getting TestDefinition %s in namespace %s", name, namespace)
+	}
+
+	podLabels, _, err := unstructured.NestedStringMap(u.Object, "spec", "template", "metadata", "labels")
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading pod template labels of TestDefinition %s in namespace %s", name, namespace)
+	}
+
+	result := map[string]string{}
+	for k, v := range podLabels {
+		result[k] = v
+	}
+	for k, v := range u.GetLabels() {
+		result[k] = v
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package testdefinition
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestTestDefinition_Labels(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	definition := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "testing.kyma-project.io/v1alpha1",
+		"kind":       "TestDefinition",
+		"metadata": map[string]interface{}{
+			"name":      "serverless",
+			"namespace": "kyma-system",
+			"labels":    map[string]interface{}{"owner": "team-a", "type": "e2e"},
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"owner": "team-b", "app": "serverless"},
+				},
+			},
+		},
+	}}
+	testDefs := New(dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), definition))
+
+	g.Expect(testDefs.Labels("kyma-system", "serverless")).To(gomega.Equal(map[string]string{
+		"owner": "team-a",
+		"type":  "e2e",
+		"app":   "serverless",
+	}))
+
+	_, err := testDefs.Labels("default", "serverless")
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("while getting TestDefinition serverless in namespace default")))
+}
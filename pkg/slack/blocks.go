@@ -0,0 +1,110 @@
+package slack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+)
+
+// maxFailedTests limits the list of failed tests, so that the section stays below the Slack limit of 3000 characters.
+const maxFailedTests = 20
+
+// summaryBlocks renders the Block Kit summary of the suite for the parent message. The parent message text
+// is used as its heading and it stays the plain-text fallback of the message.
+func summaryBlocks(data report.MessageData, parentMessage string) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewSectionBlock(markdown(fmt.Sprintf("*%s*", parentMessage)), nil, nil),
+	}
+
+	fields := []*slack.TextBlockObject{
+		markdown(fmt.Sprintf("*Platform*\n%s", data.Platform)),
+	}
+	if data.Cluster.KubernetesVersion != "" {
+		fields = append(fields, markdown(fmt.Sprintf("*Kubernetes*\n%s", data.Cluster.KubernetesVersion)))
+	}
+	if len(data.Suite.Conditions) > 0 {
+		fields = append(fields, markdown(fmt.Sprintf("*Conditions*\n%s", conditionsText(data.Suite.Conditions))))
+	}
+	if len(data.Suite.Tests) > 0 {
+		fields = append(fields, markdown(fmt.Sprintf("*Tests*\n%s", statusCountsText(data.Suite.StatusCounts()))))
+	}
+	blocks = append(blocks, slack.NewSectionBlock(nil, fields, nil))
+
+	if failed := failedTestsText(data.Suite.Tests); failed != "" {
+		blocks = append(blocks,
+			slack.NewDividerBlock(),
+			slack.NewSectionBlock(markdown(fmt.Sprintf("*Failed tests*\n%s", failed)), nil, nil),
+		)
+	}
+	return blocks
+}
+
+func markdown(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
+}
+
+func conditionsText(conditions []report.Condition) string {
+	var lines []string
+	for _, cond := range conditions {
+		line := fmt.Sprintf("%s: %s", cond.Type, cond.Status)
+		if cond.Reason != "" {
+			line += fmt.Sprintf(" (%s)", cond.Reason)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func statusCountsText(counts map[string]int) string {
+	var statuses []string
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	var lines []string
+	for _, status := range statuses {
+		lines = append(lines, fmt.Sprintf("%s: %d", status, counts[status]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// failedTestsText lists failed tests with their duration and number of retries, or returns an empty string if there are none.
+func failedTestsText(tests []report.TestSummary) string {
+	var lines []string
+	failed := 0
+	for _, test := range tests {
+		if test.Status != "Failed" {
+			continue
+		}
+		failed++
+		if failed > maxFailedTests {
+			continue
+		}
+
+		line := fmt.Sprintf("• `%s` in %s", test.Name, test.Namespace)
+		var details []string
+		if test.Duration > 0 {
+			details = append(details, test.Duration.Round(time.Second).String())
+		}
+		if test.Retries == 1 {
+			details = append(details, "1 retry")
+		} else if test.Retries > 1 {
+			details = append(details, fmt.Sprintf("%d retries", test.Retries))
+		}
+		if len(details) > 0 {
+			line += fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+		}
+		lines = append(lines, line)
+	}
+
+	if failed > maxFailedTests {
+		lines = append(lines, fmt.Sprintf("… and %d more", failed-maxFailedTests))
+	}
+	return strings.Join(lines, "\n")
+}
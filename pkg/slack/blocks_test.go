@@ -0,0 +1,74 @@
+package slack
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/slack-go/slack"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+)
+
+func TestSummaryBlocks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	data := report.MessageData{
+		Suite: report.Suite{
+			Name:       "cts",
+			Conditions: []report.Condition{{Type: "Failed", Status: "True", Reason: "TestFailed"}},
+			Tests: []report.TestSummary{
+				{Name: "serverless", Namespace: "kyma-system", Status: "Failed", Duration: 200*time.Second + 300*time.Millisecond, Retries: 2},
+				{Name: "rafter", Namespace: "rafter", Status: "Failed", Retries: 1},
+				{Name: "monitoring", Namespace: "kyma-system", Status: "Succeeded"},
+			},
+		},
+		Platform: "GKE",
+		Cluster:  report.Cluster{KubernetesVersion: "v1.16.0"},
+	}
+
+	blocks := summaryBlocks(data, "ClusterTestSuite cts")
+
+	g.Expect(blocks).To(gomega.HaveLen(4))
+	g.Expect(blocks[0].(*slack.SectionBlock).Text.Text).To(gomega.Equal("*ClusterTestSuite cts*"))
+
+	var fields []string
+	for _, field := range blocks[1].(*slack.SectionBlock).Fields {
+		fields = append(fields, field.Text)
+	}
+	g.Expect(fields).To(gomega.Equal([]string{
+		"*Platform*\nGKE",
+		"*Kubernetes*\nv1.16.0",
+		"*Conditions*\nFailed: True (TestFailed)",
+		"*Tests*\nFailed: 2\nSucceeded: 1",
+	}))
+
+	g.Expect(blocks[2]).To(gomega.BeAssignableToTypeOf(&slack.DividerBlock{}))
+	g.Expect(blocks[3].(*slack.SectionBlock).Text.Text).To(gomega.Equal("*Failed tests*\n" +
+		"• `serverless` in kyma-system (3m20s, 2 retries)\n" +
+		"• `rafter` in rafter (1 retry)"))
+}
+
+func TestSummaryBlocks_withoutFailures(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	blocks := summaryBlocks(report.MessageData{Platform: "GKE"}, "ClusterTestSuite cts")
+
+	g.Expect(blocks).To(gomega.HaveLen(2))
+	g.Expect(blocks[1].(*slack.SectionBlock).Fields).To(gomega.HaveLen(1))
+}
+
+func Test_failedTestsText(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	var tests []report.TestSummary
+	for i := 0; i < maxFailedTests+3; i++ {
+		tests = append(tests, report.TestSummary{Name: fmt.Sprintf("test-%d", i), Namespace: "default", Status: "Failed"})
+	}
+
+	text := failedTestsText(tests)
+
+	g.Expect(text).To(gomega.HavePrefix("• `test-0` in default\n"))
+	g.Expect(text).To(gomega.HaveSuffix("• `test-19` in default\n… and 3 more"))
+}
@@ -0,0 +1,89 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+)
+
+// channelsMaxAge tells how long the list of channels is cached. A channel which isn't on the list,
+// e.g. because it has been created recently, triggers a refresh once the list is older than that.
+const channelsMaxAge = 10 * time.Minute
+
+type conversationsLister interface {
+	GetConversations(params *slack.GetConversationsParameters) ([]slack.Channel, string, error)
+}
+
+// ChannelResolver finds IDs of channels by their names using the conversations API.
+// The list of channels is fetched once and cached, so that resolving many channels costs a single listing.
+type ChannelResolver struct {
+	client conversationsLister
+
+	mu       sync.Mutex
+	ids      map[string]string
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+func NewChannelResolver(client conversationsLister) *ChannelResolver {
+	return &ChannelResolver{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// ChannelID returns the ID of the channel with given name, with or without the leading "#".
+func (r *ChannelResolver) ChannelID(name string) (string, error) {
+	name = strings.TrimPrefix(name, "#")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id, ok := r.ids[name]; ok {
+		return id, nil
+	}
+
+	if r.ids != nil && r.now().Sub(r.loadedAt) < channelsMaxAge {
+		return "", fmt.Errorf("channel #%s not found", name)
+	}
+
+	if err := r.load(); err != nil {
+		return "", errors.Wrap(err, "while listing Slack channels")
+	}
+
+	if id, ok := r.ids[name]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("channel #%s not found", name)
+}
+
+func (r *ChannelResolver) load() error {
+	ids := map[string]string{}
+	params := &slack.GetConversationsParameters{
+		ExcludeArchived: "true",
+		Limit:           1000,
+		Types:           []string{"public_channel", "private_channel"},
+	}
+
+	for {
+		channels, cursor, err := r.client.GetConversations(params)
+		if err != nil {
+			return err
+		}
+		for _, ch := range channels {
+			ids[ch.Name] = ch.ID
+		}
+		if cursor == "" {
+			break
+		}
+		params.Cursor = cursor
+	}
+
+	r.ids = ids
+	r.loadedAt = r.now()
+	return nil
+}
@@ -0,0 +1,70 @@
+package slack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/slack-go/slack"
+)
+
+type fakeConversations struct {
+	pages [][]slack.Channel
+	calls int
+}
+
+func (f *fakeConversations) GetConversations(params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	f.calls++
+	page := 0
+	if params.Cursor != "" {
+		page = int(params.Cursor[0] - '0')
+	}
+	next := ""
+	if page+1 < len(f.pages) {
+		next = string(rune('0' + page + 1))
+	}
+	return f.pages[page], next, nil
+}
+
+func channel(name, id string) slack.Channel {
+	ch := slack.Channel{}
+	ch.Name = name
+	ch.ID = id
+	return ch
+}
+
+func TestChannelResolver_ChannelID(t *testing.T) {
+	t.Run("resolves channels from every page", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		lister := &fakeConversations{pages: [][]slack.Channel{
+			{channel("work", "C014YQ2R44E")},
+			{channel("serverless-test", "C0164BCSY75")},
+		}}
+		r := NewChannelResolver(lister)
+
+		g.Expect(r.ChannelID("#serverless-test")).To(gomega.Equal("C0164BCSY75"))
+		g.Expect(r.ChannelID("work")).To(gomega.Equal("C014YQ2R44E"))
+		g.Expect(lister.calls).To(gomega.Equal(2))
+	})
+
+	t.Run("refreshes cache only after it gets old", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		lister := &fakeConversations{pages: [][]slack.Channel{{channel("work", "C014YQ2R44E")}}}
+		r := NewChannelResolver(lister)
+		now := time.Now()
+		r.now = func() time.Time { return now }
+
+		_, err := r.ChannelID("#new")
+		g.Expect(err).To(gomega.MatchError("channel #new not found"))
+		_, err = r.ChannelID("#new")
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(lister.calls).To(gomega.Equal(1))
+
+		lister.pages = [][]slack.Channel{{channel("work", "C014YQ2R44E"), channel("new", "C0164BCSY75")}}
+		now = now.Add(channelsMaxAge)
+		g.Expect(r.ChannelID("#new")).To(gomega.Equal("C0164BCSY75"))
+		g.Expect(lister.calls).To(gomega.Equal(2))
+	})
+}
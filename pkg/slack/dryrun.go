@@ -0,0 +1,165 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/slack-go/slack"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+)
+
+// DryRun renders everything the Slack client would send, without contacting Slack.
+// The summary is written to out. Log files are written to dir, or inline to out if dir is empty.
+type DryRun struct {
+	out       io.Writer
+	dir       string
+	templates *report.Templates
+}
+
+func NewDryRun(out io.Writer, dir string, templates *report.Templates) *DryRun {
+	return &DryRun{
+		out:       out,
+		dir:       dir,
+		templates: templates,
+	}
+}
+
+func (d DryRun) Notify(rep report.Report) error {
+	parentMessage, err := d.templates.ParentMessage(parentMessageData(rep))
+	if err != nil {
+		return err
+	}
+
+	blocks, err := json.Marshal(slack.Blocks{BlockSet: summaryBlocks(parentMessageData(rep), parentMessage)})
+	if err != nil {
+		return errors.Wrap(err, "while rendering summary blocks")
+	}
+
+	messages := (CLient{}).groupMessagesByChannelID(messagesFromReport(rep))
+	problems := groupProblemsByChannelID(rep)
+
+	for _, channelID := range sortedChannelIDs(messages, problems) {
+		channelName := channelID
+		switch {
+		case len(messages[channelID]) > 0:
+			channelName = messages[channelID][0].ChannelName
+		default:
+			for _, route := range problems[channelID][0].Routes {
+				if route.ChannelID == channelID && route.ChannelName != "" {
+					channelName = route.ChannelName
+				}
+			}
+		}
+
+		d.printf("=== channel %s (%s)\n", channelName, channelID)
+		d.printf("parent message: %s\n", parentMessage)
+		d.printf("parent message blocks: %s\n", blocks)
+
+		for i, msg := range messages[channelID] {
+			if msg.SummaryOnly {
+				comment, err := d.templates.Comment(msg.Data)
+				if err != nil {
+					return errors.Wrapf(err, "while rendering summary of %s test case", msg.Data.Test.Name)
+				}
+				d.printf("--- thread message\n%s\n", comment)
+				continue
+			}
+			if err := d.renderFile(channelName, i, msg); err != nil {
+				return errors.Wrapf(err, "while rendering logs for %s test case", msg.Data.Test.Name)
+			}
+		}
+
+		if len(problems[channelID]) > 0 {
+			d.printf("--- thread message\n%s\n", problemsMessage(problems[channelID]))
+		}
+		d.printf("\n")
+	}
+
+	if len(rep.Skipped) > 0 {
+		d.printf("=== skipped tests\n")
+		for _, skipped := range rep.Skipped {
+			d.printf("- %s (status %s): %s\n", skipped.Test, skipped.Status, skipped.Reason)
+		}
+	}
+
+	return nil
+}
+
+func (d DryRun) renderFile(channelName string, index int, msg Message) error {
+	name, err := d.templates.Filename(msg.Data)
+	if err != nil {
+		return err
+	}
+	title, err := d.templates.Title(msg.Data)
+	if err != nil {
+		return err
+	}
+	comment, err := d.templates.Comment(msg.Data)
+	if err != nil {
+		return err
+	}
+
+	d.printf("--- file %s\n", name)
+	d.printf("title: %s\n", title)
+	d.printf("comment: %s\n", comment)
+
+	logs, err := msg.Log.Open()
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	if d.dir == "" {
+		if _, err := io.Copy(d.out, logs); err != nil {
+			return err
+		}
+		d.printf("\n")
+		return nil
+	}
+
+	channelDir := filepath.Join(d.dir, strings.TrimPrefix(channelName, "#"))
+	if err := os.MkdirAll(channelDir, 0755); err != nil {
+		return err
+	}
+
+	// rendered filenames may contain anything, they can't escape the channel directory
+	name = strings.Replace(name, string(filepath.Separator), "_", -1)
+	path := filepath.Join(channelDir, fmt.Sprintf("%03d-%s-%s", index, msg.Data.Test.Name, name))
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, logs); err != nil {
+		return err
+	}
+	d.printf("written to %s\n", path)
+	return nil
+}
+
+func (d DryRun) printf(format string, args ...interface{}) {
+	// errors of the summary output are deliberately ignored, there's nothing sensible to do about them
+	_, _ = fmt.Fprintf(d.out, format, args...)
+}
+
+func sortedChannelIDs(messages map[string][]Message, problems map[string][]report.Problem) []string {
+	var ids []string
+	for id := range messages {
+		ids = append(ids, id)
+	}
+	for id := range problems {
+		if _, ok := messages[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
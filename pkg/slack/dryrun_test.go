@@ -0,0 +1,89 @@
+package slack
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+)
+
+func TestDryRun_Notify(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "dryrun")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		g.Expect(os.RemoveAll(dir)).To(gomega.Succeed())
+	}()
+
+	logPath := filepath.Join(dir, "input.txt")
+	g.Expect(ioutil.WriteFile(logPath, []byte("some logs"), 0644)).To(gomega.Succeed())
+
+	rep := report.Report{
+		Suite:    report.Suite{Name: "cts", CompletionTime: "now"},
+		Platform: "GKE",
+		Results: []report.TestResult{
+			{
+				Name:   "test-1",
+				Status: "Failed",
+				Executions: []report.Execution{{ID: "pod-1", Attempt: 1, PodPhase: "Failed", Decisive: true, Containers: []report.ContainerLog{
+					{Name: "test", Primary: true, Log: report.Log{Path: logPath}},
+				}}},
+				Routes: []report.Route{{ChannelName: "#chan-1", ChannelID: "id-1"}},
+			},
+			{
+				Name:   "test-4",
+				Status: "Skipped",
+				Routes: []report.Route{{ChannelName: "#chan-1", ChannelID: "id-1", SummaryOnly: true}},
+			},
+		},
+		Problems: []report.Problem{{Test: "test-2", Message: "pod not found", Routes: []report.Route{{ChannelName: "#chan-2", ChannelID: "id-2"}}}},
+		Skipped:  []report.Skipped{{Test: "test-3", Status: "Succeeded", Reason: "it has status Succeeded and its route reports only failures"}},
+	}
+
+	t.Run("renders logs inline", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		out := &bytes.Buffer{}
+		g.Expect(NewDryRun(out, "", report.DefaultTemplates()).Notify(rep)).To(gomega.Succeed())
+		g.Expect(out.String()).To(gomega.Equal(`=== channel #chan-1 (id-1)
+parent message: ClusterTestSuite cts, completionTime now, platform GKE
+parent message blocks: [{"type":"section","text":{"type":"mrkdwn","text":"*ClusterTestSuite cts, completionTime now, platform GKE*"}},{"type":"section","fields":[{"type":"mrkdwn","text":"*Platform*\nGKE"}]}]
+--- file logs.txt
+title: Test logs
+comment: Test test-1, status: Failed, attempt 1/1 (pod pod-1), pod phase: Failed, this attempt decided the final status, container: test
+some logs
+--- thread message
+Test test-4, status: Skipped
+
+=== channel #chan-2 (id-2)
+parent message: ClusterTestSuite cts, completionTime now, platform GKE
+parent message blocks: [{"type":"section","text":{"type":"mrkdwn","text":"*ClusterTestSuite cts, completionTime now, platform GKE*"}},{"type":"section","fields":[{"type":"mrkdwn","text":"*Platform*\nGKE"}]}]
+--- thread message
+Collection problems:
+• test test-2: pod not found
+
+=== skipped tests
+- test-3 (status Succeeded): it has status Succeeded and its route reports only failures
+`))
+	})
+
+	t.Run("writes logs to directory", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		out := &bytes.Buffer{}
+		g.Expect(NewDryRun(out, dir, report.DefaultTemplates()).Notify(rep)).To(gomega.Succeed())
+
+		path := filepath.Join(dir, "chan-1", "000-test-1-logs.txt")
+		g.Expect(out.String()).To(gomega.ContainSubstring("written to " + path))
+
+		content, err := ioutil.ReadFile(path)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(string(content)).To(gomega.Equal("some logs"))
+	})
+}
@@ -0,0 +1,154 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	logf "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// uploadInterval paces uploads to a single channel, Slack allows roughly 20 uploads per minute
+	uploadInterval = 3 * time.Second
+	uploadBurst    = 5
+
+	// maxRateLimitedRetries bounds retries of a request which keeps being rate limited
+	maxRateLimitedRetries = 10
+)
+
+// retryBackoff is used for transient errors. Rate limiting errors wait as long as Slack asks instead.
+var retryBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
+// RetryingClient calls the Slack API honouring its rate limits. Requests which have been rate limited or failed
+// with a transient error are retried, uploads to every channel are paced, so that big reports don't hit the limits.
+// Messages are retried only if they've been rate limited, as after a timeout or a server error they may have been
+// already posted.
+type RetryingClient struct {
+	client  slackClient
+	backoff wait.Backoff
+	sleep   func(time.Duration)
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func NewRetryingClient(client slackClient) *RetryingClient {
+	return &RetryingClient{
+		client:   client,
+		backoff:  retryBackoff,
+		sleep:    time.Sleep,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+func (c *RetryingClient) GetConversationHistory(params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	var resp *slack.GetConversationHistoryResponse
+	err := c.retry("conversations.history", true, func() error {
+		var err error
+		resp, err = c.client.GetConversationHistory(params)
+		return err
+	})
+	return resp, err
+}
+
+func (c *RetryingClient) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	var channel, ts string
+	err := c.retry("chat.postMessage", false, func() error {
+		var err error
+		channel, ts, err = c.client.PostMessage(channelID, options...)
+		return err
+	})
+	return channel, ts, err
+}
+
+// UploadFile uploads the file once the channels it's shared to are ready for another upload.
+// The file is read again on retry, so only seekable readers can be retried.
+func (c *RetryingClient) UploadFile(params slack.FileUploadParameters) (*slack.File, error) {
+	for _, channel := range params.Channels {
+		if err := c.limiter(channel).Wait(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+
+	var file *slack.File
+	attempt := 0
+	err := c.retry("files.upload", true, func() error {
+		if attempt > 0 && params.Reader != nil {
+			seeker, ok := params.Reader.(io.Seeker)
+			if !ok {
+				return errors.New("file can't be read again")
+			}
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return errors.Wrap(err, "while rewinding file")
+			}
+		}
+		attempt++
+
+		var err error
+		file, err = c.client.UploadFile(params)
+		return err
+	})
+	return file, err
+}
+
+// retry calls fn until it succeeds, fails with an error which isn't worth retrying or the backoff runs out.
+// Transient errors are retried only if retryTransient is set, rate limiting errors always are.
+func (c *RetryingClient) retry(method string, retryTransient bool, fn func() error) error {
+	backoff := c.backoff
+	rateLimited := 0
+	for {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		var delay time.Duration
+		if limited, ok := errors.Cause(err).(*slack.RateLimitedError); ok && rateLimited < maxRateLimitedRetries {
+			rateLimited++
+			delay = limited.RetryAfter
+		} else if !ok && retryTransient && transient(err) && backoff.Steps > 1 {
+			delay = backoff.Step()
+		} else {
+			return err
+		}
+
+		logf.Warnf("%s failed, retrying in %s: %s", method, delay, err)
+		c.sleep(delay)
+	}
+}
+
+// transient tells whether the request may succeed when repeated, e.g. after a network error or a server error.
+func transient(err error) bool {
+	cause := errors.Cause(err)
+	if retryable, ok := cause.(interface{ Retryable() bool }); ok {
+		return retryable.Retryable()
+	}
+	if netErr, ok := cause.(net.Error); ok {
+		return netErr.Temporary() || netErr.Timeout()
+	}
+	return false
+}
+
+func (c *RetryingClient) limiter(channelID string) *rate.Limiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	limiter, ok := c.limiters[channelID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(uploadInterval), uploadBurst)
+		c.limiters[channelID] = limiter
+	}
+	return limiter
+}
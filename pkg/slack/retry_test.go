@@ -0,0 +1,172 @@
+package slack
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/slack-go/slack"
+)
+
+type retryableError bool
+
+func (e retryableError) Error() string {
+	return "slack server error"
+}
+
+func (e retryableError) Retryable() bool {
+	return bool(e)
+}
+
+// failingSlack fails calls with given errors before it succeeds.
+type failingSlack struct {
+	fakeSlack
+	errs     []error
+	uploaded []string
+}
+
+func (f *failingSlack) next() error {
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *failingSlack) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	if err := f.next(); err != nil {
+		return "", "", err
+	}
+	return f.fakeSlack.PostMessage(channelID, options...)
+}
+
+func (f *failingSlack) UploadFile(params slack.FileUploadParameters) (*slack.File, error) {
+	content, err := ioutil.ReadAll(params.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	f.uploaded = append(f.uploaded, string(content))
+	return &slack.File{}, nil
+}
+
+func TestRetryingClient(t *testing.T) {
+	newClient := func(errs ...error) (*RetryingClient, *failingSlack, *[]time.Duration) {
+		fake := &failingSlack{errs: errs}
+		var sleeps []time.Duration
+		c := NewRetryingClient(fake)
+		c.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+		return c, fake, &sleeps
+	}
+
+	t.Run("waits as long as Slack asks when rate limited", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		c, fake, sleeps := newClient(&slack.RateLimitedError{RetryAfter: 30 * time.Second})
+
+		_, ts, err := c.PostMessage("C0164BCSY75")
+
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(ts).To(gomega.Equal("1594728000.000300"))
+		g.Expect(fake.posted).To(gomega.HaveLen(1))
+		g.Expect(*sleeps).To(gomega.Equal([]time.Duration{30 * time.Second}))
+	})
+
+	t.Run("retries transient errors with backoff", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		c, fake, sleeps := newClient(retryableError(true), retryableError(true))
+
+		_, err := c.UploadFile(slack.FileUploadParameters{Reader: strings.NewReader("logs"), Channels: []string{"C0164BCSY75"}})
+
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(fake.uploaded).To(gomega.Equal([]string{"logs"}))
+		g.Expect(*sleeps).To(gomega.HaveLen(2))
+		g.Expect((*sleeps)[1]).To(gomega.BeNumerically(">", (*sleeps)[0]))
+	})
+
+	t.Run("gives up once backoff runs out", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		var errs []error
+		for i := 0; i < retryBackoff.Steps; i++ {
+			errs = append(errs, retryableError(true))
+		}
+		c, fake, sleeps := newClient(errs...)
+
+		_, err := c.UploadFile(slack.FileUploadParameters{Reader: strings.NewReader("logs"), Channels: []string{"C0164BCSY75"}})
+
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(fake.uploaded).To(gomega.BeEmpty())
+		g.Expect(*sleeps).To(gomega.HaveLen(retryBackoff.Steps - 1))
+	})
+
+	t.Run("doesn't post message again after transient error", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		c, fake, sleeps := newClient(retryableError(true))
+
+		_, _, err := c.PostMessage("C0164BCSY75")
+
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(fake.posted).To(gomega.BeEmpty())
+		g.Expect(*sleeps).To(gomega.BeEmpty())
+	})
+
+	t.Run("doesn't retry other errors", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		c, _, sleeps := newClient(errors.New("channel_not_found"), retryableError(false))
+
+		_, _, err := c.PostMessage("C0164BCSY75")
+		g.Expect(err).To(gomega.MatchError("channel_not_found"))
+		_, _, err = c.PostMessage("C0164BCSY75")
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(*sleeps).To(gomega.BeEmpty())
+	})
+
+	t.Run("uploads the whole file again on retry", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		c, fake, _ := newClient(&slack.RateLimitedError{RetryAfter: time.Second})
+		file, err := ioutil.TempFile("", "logs")
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		defer file.Close()
+		_, err = file.WriteString("logs of the test")
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		_, err = file.Seek(0, 0)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+
+		_, err = c.UploadFile(slack.FileUploadParameters{Reader: file, Channels: []string{"C0164BCSY75"}})
+
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(fake.uploaded).To(gomega.Equal([]string{"logs of the test"}))
+	})
+
+	t.Run("doesn't retry upload which can't be read again", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		c, fake, _ := newClient(&slack.RateLimitedError{RetryAfter: time.Second})
+
+		_, err := c.UploadFile(slack.FileUploadParameters{Reader: ioutil.NopCloser(strings.NewReader("logs")), Channels: []string{"C0164BCSY75"}})
+
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(fake.uploaded).To(gomega.BeEmpty())
+	})
+}
+
+func TestRetryingClient_limiter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	c := NewRetryingClient(&fakeSlack{})
+
+	g.Expect(c.limiter("C0164BCSY75")).To(gomega.BeIdenticalTo(c.limiter("C0164BCSY75")))
+	g.Expect(c.limiter("C0164BCSY75")).ToNot(gomega.BeIdenticalTo(c.limiter("C014YQ2R44E")))
+	g.Expect(c.limiter("C0164BCSY75").Burst()).To(gomega.Equal(uploadBurst))
+}
@@ -6,93 +6,204 @@ import (
 	"github.com/pkg/errors"
 	logf "github.com/sirupsen/logrus"
 	"github.com/slack-go/slack"
-)
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
-type Attributes struct {
-	Name             string
-	Status           string
-	ClusterTestSuite string
-	CompletionTime   string
-	Platform         string
-}
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+)
 
 type Message struct {
-	Data        string
-	Attributes  Attributes
+	Log report.Log
+	// Data is passed to the comment, filename and title templates
+	Data        report.MessageData
 	ChannelName string
 	ChannelID   string
+	// SummaryOnly messages are sent as a single line in the thread instead of a log file
+	SummaryOnly bool
+}
+
+// slackClient is the part of the Slack API used to deliver reports.
+type slackClient interface {
+	GetConversationHistory(params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
+	PostMessage(channelID string, options ...slack.MsgOption) (string, string, error)
+	UploadFile(params slack.FileUploadParameters) (*slack.File, error)
+}
+
+// ThreadStore remembers threads of the suites' reports, so that every report of a suite goes to the same threads.
+type ThreadStore interface {
+	// Threads returns timestamps of parent messages of the suite by channel ID
+	Threads(suite string) (map[string]string, error)
+	SaveThread(suite, channelID, timestamp string) error
 }
 
 type CLient struct {
-	client *slack.Client
+	client    slackClient
+	templates *report.Templates
+	// store is optional, without it threads are found by the text of the parent message only
+	store ThreadStore
 }
 
-func New(client *slack.Client) *CLient {
+func New(client slackClient, templates *report.Templates, store ThreadStore) *CLient {
 	return &CLient{
-		client: client,
+		client:    client,
+		templates: templates,
+		store:     store,
+	}
+}
+
+// Notify implements notifier.Notifier by uploading logs of every test result to its Slack channel.
+// Problems are posted as a separate message in the thread of every channel they're routed to.
+func (s CLient) Notify(rep report.Report) error {
+	parentMessage, err := s.templates.ParentMessage(parentMessageData(rep))
+	if err != nil {
+		return err
 	}
+
+	parents := s.newThreads(rep.Suite, parentMessage, summaryBlocks(parentMessageData(rep), parentMessage))
+	// problems are posted even if some logs couldn't be delivered, so that the thread tells what's missing
+	uploadErr := s.uploadLogFiles(messagesFromReport(rep), parents)
+	problemsErr := s.postProblems(rep, parents)
+	return utilerrors.NewAggregate([]error{uploadErr, problemsErr})
 }
 
-func (s CLient) parentMessageTimestamp(hist slack.History, parentMsg string) (string, bool) {
-	for _, msg := range hist.Messages {
-		if msg.Text == parentMsg {
-			return msg.Timestamp, true
+// postProblems sends the "collection problems" section to the thread of every channel the problems are routed to.
+func (s CLient) postProblems(rep report.Report, parents *threads) error {
+	for channelID, problems := range groupProblemsByChannelID(rep) {
+		parentMsgTimestamp, err := parents.timestamp(channelID)
+		if err != nil {
+			return errors.Wrapf(err, "in channel %s", channelID)
+		}
+
+		logf.Info("posting collection problems")
+		_, _, err = s.client.PostMessage(channelID,
+			slack.MsgOptionText(problemsMessage(problems), false),
+			slack.MsgOptionTS(parentMsgTimestamp),
+		)
+		if err != nil {
+			return errors.Wrapf(err, "while posting collection problems to channel %s", channelID)
 		}
 	}
-	return "", false
+	return nil
 }
 
-func (s CLient) createParentMessage(ctsName, channelID, completionTime, platform string) error {
-	hist, err := s.client.GetChannelHistory(channelID, slack.HistoryParameters{
-		Count: 100,
-	})
-	if err != nil {
-		return errors.Wrapf(err, "while getting channel historical messages by id: %s", channelID)
+// groupProblemsByChannelID assigns problems to channels. Problems without a route go to every channel used by the report.
+func groupProblemsByChannelID(rep report.Report) map[string][]report.Problem {
+	var channels []string
+	for _, result := range rep.Results {
+		for _, route := range result.Routes {
+			if !containsString(channels, route.ChannelID) {
+				channels = append(channels, route.ChannelID)
+			}
+		}
 	}
 
-	parentMessage := fmt.Sprintf("ClusterTestSuite %s, completionTime %s, platform %s", ctsName, completionTime, platform)
+	mp := make(map[string][]report.Problem)
+	for _, p := range rep.Problems {
+		if len(p.Routes) > 0 {
+			for _, route := range p.Routes {
+				mp[route.ChannelID] = append(mp[route.ChannelID], p)
+			}
+			continue
+		}
+		for _, channelID := range channels {
+			mp[channelID] = append(mp[channelID], p)
+		}
+	}
+	return mp
+}
 
-	_, exists := s.parentMessageTimestamp(*hist, parentMessage)
-	if exists {
-		logf.Info("parent message already exists")
-		return nil
+func containsString(slice []string, element string) bool {
+	for _, s := range slice {
+		if s == element {
+			return true
+		}
 	}
+	return false
+}
 
-	logf.Info("creating parent message")
+func problemsMessage(problems []report.Problem) string {
+	msg := "Collection problems:"
+	for _, p := range problems {
+		msg += fmt.Sprintf("\n• %s", p)
+	}
+	return msg
+}
 
-	_, _, err = s.client.PostMessage(channelID, slack.MsgOptionText(parentMessage, false))
-	if err != nil {
-		return errors.Wrap(err, "while creating slack thread")
+func parentMessageData(rep report.Report) report.MessageData {
+	return report.MessageData{
+		Suite:    rep.Suite,
+		Platform: rep.Platform,
+		Cluster:  rep.Cluster,
 	}
-	return nil
 }
 
-func (s CLient) UploadLogFiles(messages []Message, ctsName, completionTime, platform string) error {
-	for channelID, messageSlice := range s.groupMessagesByChannelID(messages) {
-		if err := s.createParentMessage(ctsName, channelID, completionTime, platform); err != nil {
-			return errors.Wrapf(err, "while creating parent slack message in channel %s", messageSlice[0].ChannelName)
+func messagesFromReport(rep report.Report) []Message {
+	var messages []Message
+	for i := range rep.Results {
+		for _, route := range rep.Results[i].Routes {
+			messages = append(messages, routeMessages(rep, &rep.Results[i], route)...)
 		}
+	}
+	return messages
+}
 
-		// get channel history has to be called here *again*, otherwise slack api acts crazy
-		hist, err := s.client.GetChannelHistory(channelID, slack.HistoryParameters{
-			Count: 100, // it should be more than enough
-		})
-		if err != nil {
-			return errors.Wrapf(err, "while getting %s channel historical messages", messageSlice[0].ChannelName)
-		}
+// routeMessages returns messages which deliver the test result to a single channel.
+func routeMessages(rep report.Report, result *report.TestResult, route report.Route) []Message {
+	data := parentMessageData(rep)
+	data.Test = result
 
-		parentMessage := fmt.Sprintf("ClusterTestSuite %s, completionTime %s, platform %s", ctsName, completionTime, platform)
+	if route.SummaryOnly {
+		return []Message{{
+			Data:        data,
+			ChannelName: route.ChannelName,
+			ChannelID:   route.ChannelID,
+			SummaryOnly: true,
+		}}
+	}
 
-		parentMsgTimestamp, _ := s.parentMessageTimestamp(*hist, parentMessage)
+	var messages []Message
+	for i := range result.Executions {
+		exec := &result.Executions[i]
+		for j := range exec.Containers {
+			containerData := data
+			containerData.Execution = exec
+			containerData.Container = &exec.Containers[j]
+
+			messages = append(messages, Message{
+				Log:         exec.Containers[j].Log,
+				Data:        containerData,
+				ChannelName: route.ChannelName,
+				ChannelID:   route.ChannelID,
+			})
+		}
+	}
+	return messages
+}
+
+// uploadLogFiles delivers messages to the thread of every channel. A message which can't be delivered doesn't stop
+// the others, every failure is returned.
+func (s CLient) uploadLogFiles(messages []Message, parents *threads) error {
+	var errs []error
+	for channelID, messageSlice := range s.groupMessagesByChannelID(messages) {
+		parentMsgTimestamp, err := parents.timestamp(channelID)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "in channel %s", messageSlice[0].ChannelName))
+			continue
+		}
 
 		for _, msg := range messageSlice {
+			if msg.SummaryOnly {
+				if err := s.PostSummary(msg, parentMsgTimestamp); err != nil {
+					errs = append(errs, errors.Wrapf(err, "while posting summary of %s test case", msg.Data.Test.Name))
+				}
+				continue
+			}
 			if err := s.UploadLogFile(msg, parentMsgTimestamp); err != nil {
-				return errors.Wrapf(err, "while uploading logs for %s test case", msg.Attributes.Name)
+				errs = append(errs, errors.Wrapf(err, "while uploading logs for %s test case", msg.Data.Test.Name))
 			}
 		}
 	}
 
-	return nil
+	return utilerrors.NewAggregate(errs)
 }
 
 func (s CLient) groupMessagesByChannelID(messages []Message) map[string][]Message {
@@ -103,13 +214,47 @@ func (s CLient) groupMessagesByChannelID(messages []Message) map[string][]Messag
 	return mp
 }
 
+// PostSummary sends a single line describing the test to the thread.
+func (s CLient) PostSummary(msg Message, parentMsgTimestamp string) error {
+	comment, err := s.templates.Comment(msg.Data)
+	if err != nil {
+		return err
+	}
+
+	logf.Info("posting test summary")
+	_, _, err = s.client.PostMessage(msg.ChannelID,
+		slack.MsgOptionText(comment, false),
+		slack.MsgOptionTS(parentMsgTimestamp),
+	)
+	return err
+}
+
 func (s CLient) UploadLogFile(msg Message, parentMsgTimestamp string) error {
+	comment, err := s.templates.Comment(msg.Data)
+	if err != nil {
+		return err
+	}
+	name, err := s.templates.Filename(msg.Data)
+	if err != nil {
+		return err
+	}
+	title, err := s.templates.Title(msg.Data)
+	if err != nil {
+		return err
+	}
+
 	logf.Info("uploading log file")
-	_, err := s.client.UploadFile(slack.FileUploadParameters{
-		Content:        msg.Data,
-		Filename:       "logs.txt",
-		Title:          "Test logs",
-		InitialComment: fmt.Sprintf("Test %s, status: %s", msg.Attributes.Name, msg.Attributes.Status),
+	logs, err := msg.Log.Open()
+	if err != nil {
+		return errors.Wrapf(err, "while opening logs of %s test case", msg.Data.Test.Name)
+	}
+	defer logs.Close()
+
+	_, err = s.client.UploadFile(slack.FileUploadParameters{
+		Reader:         logs,
+		Filename:       name,
+		Title:          title,
+		InitialComment: comment,
 		Channels: []string{
 			msg.ChannelID,
 		},
@@ -1,9 +1,12 @@
 package slack
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/onsi/gomega"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
 )
 
 func TestCLient_groupMessagesByChannelID(t *testing.T) {
@@ -15,19 +18,19 @@ func TestCLient_groupMessagesByChannelID(t *testing.T) {
 		{
 			name: "simple",
 			args: []Message{
-				{ChannelID: "test-name-1", Data: "data-1"},
-				{ChannelID: "test-name-1", Data: "data-2"},
-				{ChannelID: "test-name-1", Data: "data-3"},
-				{ChannelID: "test-name-2", Data: "data-4"},
+				{ChannelID: "test-name-1", Log: report.Log{Path: "data-1"}},
+				{ChannelID: "test-name-1", Log: report.Log{Path: "data-2"}},
+				{ChannelID: "test-name-1", Log: report.Log{Path: "data-3"}},
+				{ChannelID: "test-name-2", Log: report.Log{Path: "data-4"}},
 			},
 			want: map[string][]Message{
 				"test-name-1": {
-					{ChannelID: "test-name-1", Data: "data-1"},
-					{ChannelID: "test-name-1", Data: "data-2"},
-					{ChannelID: "test-name-1", Data: "data-3"},
+					{ChannelID: "test-name-1", Log: report.Log{Path: "data-1"}},
+					{ChannelID: "test-name-1", Log: report.Log{Path: "data-2"}},
+					{ChannelID: "test-name-1", Log: report.Log{Path: "data-3"}},
 				},
 				"test-name-2": {
-					{ChannelID: "test-name-2", Data: "data-4"},
+					{ChannelID: "test-name-2", Log: report.Log{Path: "data-4"}},
 				},
 			},
 		},
@@ -41,3 +44,106 @@ func TestCLient_groupMessagesByChannelID(t *testing.T) {
 		})
 	}
 }
+
+func Test_messagesFromReport(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	rep := report.Report{
+		Suite:    report.Suite{Name: "cts", CompletionTime: "now"},
+		Platform: "GKE",
+		Cluster:  report.Cluster{KubernetesVersion: "v1.16.0"},
+		Results: []report.TestResult{
+			{
+				Name:   "test-1",
+				Status: "Failed",
+				Executions: []report.Execution{
+					{ID: "pod-1", Attempt: 1, PodPhase: "Failed", Containers: []report.ContainerLog{{Name: "test", Primary: true, Log: report.Log{Path: "data-1"}}}},
+					{ID: "pod-2", Attempt: 2, PodPhase: "Failed", Reason: "Error", Containers: []report.ContainerLog{{Name: "test", Primary: true, Log: report.Log{Path: "data-2"}}}, Decisive: true},
+				},
+				Routes: []report.Route{{ChannelName: "#chan-1", ChannelID: "id-1"}},
+			},
+			{
+				Name:   "test-2",
+				Status: "Succeeded",
+				Executions: []report.Execution{{ID: "pod-3", Attempt: 1, PodPhase: "Succeeded", Decisive: true, Containers: []report.ContainerLog{
+					{Name: "init", Init: true, Log: report.Log{Path: "data-3"}},
+					{Name: "test", Primary: true, Log: report.Log{Path: "data-4"}},
+				}}},
+				Routes: []report.Route{
+					{ChannelName: "#chan-2", ChannelID: "id-2"},
+					{ChannelName: "#chan-1", ChannelID: "id-1", SummaryOnly: true},
+				},
+			},
+		},
+	}
+
+	data := func(test, exec, container int) report.MessageData {
+		d := report.MessageData{Suite: rep.Suite, Platform: rep.Platform, Cluster: rep.Cluster, Test: &rep.Results[test]}
+		if exec >= 0 {
+			d.Execution = &rep.Results[test].Executions[exec]
+			d.Container = &rep.Results[test].Executions[exec].Containers[container]
+		}
+		return d
+	}
+
+	g.Expect(messagesFromReport(rep)).To(gomega.Equal([]Message{
+		{Log: report.Log{Path: "data-1"}, Data: data(0, 0, 0), ChannelName: "#chan-1", ChannelID: "id-1"},
+		{Log: report.Log{Path: "data-2"}, Data: data(0, 1, 0), ChannelName: "#chan-1", ChannelID: "id-1"},
+		{Log: report.Log{Path: "data-3"}, Data: data(1, 0, 0), ChannelName: "#chan-2", ChannelID: "id-2"},
+		{Log: report.Log{Path: "data-4"}, Data: data(1, 0, 1), ChannelName: "#chan-2", ChannelID: "id-2"},
+		{Data: data(1, -1, 0), ChannelName: "#chan-1", ChannelID: "id-1", SummaryOnly: true},
+	}))
+}
+
+func Test_groupProblemsByChannelID(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	routed := report.Problem{Test: "test-1", Message: "pod not found", Routes: []report.Route{{ChannelID: "id-1"}}}
+	unrouted := report.Problem{Test: "test-3", Message: "no dispatching config"}
+
+	rep := report.Report{
+		Results: []report.TestResult{
+			{Name: "test-1", Routes: []report.Route{{ChannelID: "id-1"}}},
+			{Name: "test-2", Routes: []report.Route{{ChannelID: "id-2"}}},
+		},
+		Problems: []report.Problem{routed, unrouted},
+	}
+
+	g.Expect(groupProblemsByChannelID(rep)).To(gomega.Equal(map[string][]report.Problem{
+		"id-1": {routed, unrouted},
+		"id-2": {unrouted},
+	}))
+}
+
+func Test_problemsMessage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	msg := problemsMessage([]report.Problem{
+		{Test: "test-1", Execution: "pod-1", Container: "test", Message: "EOF"},
+		{Test: "test-2", Message: "no dispatching config"},
+	})
+
+	g.Expect(msg).To(gomega.Equal("Collection problems:\n• test test-1, pod pod-1, container test: EOF\n• test test-2: no dispatching config"))
+}
+
+func TestCLient_Notify(t *testing.T) {
+	t.Run("delivers the rest of the report after a failure", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		// the parent message is posted first, then summaries of both tests
+		fake := &failingSlack{errs: []error{nil, errors.New("msg_too_long")}}
+		s := New(fake, report.DefaultTemplates(), nil)
+		route := report.Route{ChannelName: "#team", ChannelID: "C0164BCSY75", SummaryOnly: true}
+
+		err := s.Notify(report.Report{
+			Suite: report.Suite{Name: "cts"},
+			Results: []report.TestResult{
+				{Name: "test-1", Status: "Failed", Routes: []report.Route{route}},
+				{Name: "test-2", Status: "Failed", Routes: []report.Route{route}},
+			},
+		})
+
+		g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("while posting summary of test-1 test case: msg_too_long")))
+		g.Expect(fake.posted).To(gomega.HaveLen(2))
+	})
+}
@@ -0,0 +1,120 @@
+package slack
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	logf "github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+)
+
+// historyPageSize is the number of messages fetched at once while looking for the parent message.
+const historyPageSize = 200
+
+// threads finds or creates the parent message of the report in each channel, at most once per channel.
+// Threads known to the store are used as they are, new ones are saved there.
+type threads struct {
+	client        slackClient
+	store         ThreadStore
+	suite         string
+	parentMessage string
+	// blocks are sent with the parent message, which is their plain-text fallback
+	blocks []slack.Block
+	// since bounds the history search, messages older than the suite can't be its parent message
+	since      time.Time
+	timestamps map[string]string
+}
+
+func (s CLient) newThreads(suite report.Suite, parentMessage string, blocks []slack.Block) *threads {
+	t := &threads{
+		client:        s.client,
+		store:         s.store,
+		suite:         suite.Name,
+		parentMessage: parentMessage,
+		blocks:        blocks,
+		since:         suite.StartTime,
+		timestamps:    make(map[string]string),
+	}
+
+	if t.store != nil {
+		stored, err := t.store.Threads(suite.Name)
+		if err != nil {
+			logf.Warnf("while reading stored threads, looking for parent messages in channel history: %s", err)
+		}
+		for channelID, ts := range stored {
+			t.timestamps[channelID] = ts
+		}
+	}
+	return t
+}
+
+// timestamp returns the timestamp of the parent message in the channel. The message is posted unless it already exists.
+func (t *threads) timestamp(channelID string) (string, error) {
+	if ts, ok := t.timestamps[channelID]; ok {
+		return ts, nil
+	}
+
+	ts, found, err := t.findParentMessage(channelID)
+	if err != nil {
+		return "", errors.Wrapf(err, "while looking for parent message in channel %s", channelID)
+	}
+	if found {
+		logf.Info("parent message already exists")
+	} else {
+		logf.Info("creating parent message")
+		options := []slack.MsgOption{slack.MsgOptionText(t.parentMessage, false)}
+		if len(t.blocks) > 0 {
+			options = append(options, slack.MsgOptionBlocks(t.blocks...))
+		}
+		_, ts, err = t.client.PostMessage(channelID, options...)
+		if err != nil {
+			return "", errors.Wrap(err, "while creating slack thread")
+		}
+	}
+
+	t.timestamps[channelID] = ts
+	if t.store != nil {
+		if err := t.store.SaveThread(t.suite, channelID, ts); err != nil {
+			logf.Warnf("while storing thread of ClusterTestSuite %s in channel %s: %s", t.suite, channelID, err)
+		}
+	}
+	return ts, nil
+}
+
+// findParentMessage pages through the channel history, from the newest message back to the start of the suite.
+// If the start is unknown, only the newest page is searched.
+func (t *threads) findParentMessage(channelID string) (string, bool, error) {
+	params := &slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Limit:     historyPageSize,
+	}
+	if !t.since.IsZero() {
+		params.Oldest = slackTimestamp(t.since)
+	}
+
+	for {
+		hist, err := t.client.GetConversationHistory(params)
+		if err != nil {
+			return "", false, err
+		}
+
+		for _, msg := range hist.Messages {
+			if msg.Text == t.parentMessage {
+				return msg.Timestamp, true, nil
+			}
+		}
+
+		if t.since.IsZero() || !hist.HasMore || hist.ResponseMetaData.NextCursor == "" {
+			return "", false, nil
+		}
+		params.Cursor = hist.ResponseMetaData.NextCursor
+	}
+}
+
+// slackTimestamp formats time the way Slack identifies messages.
+func slackTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/int(time.Microsecond))
+}
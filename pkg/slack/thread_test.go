@@ -0,0 +1,149 @@
+package slack
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/slack-go/slack"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+)
+
+type fakeSlack struct {
+	// history is returned page by page, the cursor is the index of the page
+	history      [][]slack.Message
+	historyCalls []slack.GetConversationHistoryParameters
+	posted       []string
+}
+
+func (f *fakeSlack) GetConversationHistory(params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	f.historyCalls = append(f.historyCalls, *params)
+
+	resp := &slack.GetConversationHistoryResponse{}
+	if len(f.history) == 0 {
+		return resp, nil
+	}
+	page := 0
+	if params.Cursor != "" {
+		page, _ = strconv.Atoi(params.Cursor)
+	}
+	resp.Messages = f.history[page]
+	if page+1 < len(f.history) {
+		resp.HasMore = true
+		resp.ResponseMetaData.NextCursor = strconv.Itoa(page + 1)
+	}
+	return resp, nil
+}
+
+func (f *fakeSlack) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	f.posted = append(f.posted, channelID)
+	return channelID, "1594728000.000300", nil
+}
+
+func (f *fakeSlack) UploadFile(params slack.FileUploadParameters) (*slack.File, error) {
+	return &slack.File{}, nil
+}
+
+type fakeThreadStore map[string]map[string]string
+
+func (f fakeThreadStore) Threads(suite string) (map[string]string, error) {
+	return f[suite], nil
+}
+
+func (f fakeThreadStore) SaveThread(suite, channelID, timestamp string) error {
+	if f[suite] == nil {
+		f[suite] = make(map[string]string)
+	}
+	f[suite][channelID] = timestamp
+	return nil
+}
+
+func message(text, ts string) slack.Message {
+	msg := slack.Message{}
+	msg.Text = text
+	msg.Timestamp = ts
+	return msg
+}
+
+func TestThreads_timestamp(t *testing.T) {
+	suite := report.Suite{Name: "cts", StartTime: time.Date(2020, 7, 14, 12, 0, 0, 250000000, time.UTC)}
+
+	t.Run("finds parent message on a later page", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		client := &fakeSlack{history: [][]slack.Message{
+			{message("other", "1594728000.000200")},
+			{message("parent", "1594728000.000100")},
+		}}
+		parents := CLient{client: client}.newThreads(suite, "parent", nil)
+
+		g.Expect(parents.timestamp("C0164BCSY75")).To(gomega.Equal("1594728000.000100"))
+		g.Expect(client.posted).To(gomega.BeEmpty())
+		g.Expect(client.historyCalls).To(gomega.HaveLen(2))
+		g.Expect(client.historyCalls[0].Oldest).To(gomega.Equal("1594728000.250000"))
+		g.Expect(client.historyCalls[1].Cursor).To(gomega.Equal("1"))
+	})
+
+	t.Run("posts missing parent message and uses its timestamp", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		client := &fakeSlack{history: [][]slack.Message{
+			{message("other", "1594728000.000200")},
+		}}
+		parents := CLient{client: client}.newThreads(suite, "parent", nil)
+
+		g.Expect(parents.timestamp("C0164BCSY75")).To(gomega.Equal("1594728000.000300"))
+		g.Expect(client.posted).To(gomega.Equal([]string{"C0164BCSY75"}))
+	})
+
+	t.Run("looks up every channel once", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		client := &fakeSlack{}
+		parents := CLient{client: client}.newThreads(suite, "parent", nil)
+
+		g.Expect(parents.timestamp("C0164BCSY75")).To(gomega.Equal("1594728000.000300"))
+		g.Expect(parents.timestamp("C0164BCSY75")).To(gomega.Equal("1594728000.000300"))
+		g.Expect(client.historyCalls).To(gomega.HaveLen(1))
+		g.Expect(client.posted).To(gomega.HaveLen(1))
+	})
+
+	t.Run("searches only the newest page without start time", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		client := &fakeSlack{history: [][]slack.Message{
+			{message("other", "1594728000.000200")},
+			{message("parent", "1594728000.000100")},
+		}}
+		parents := CLient{client: client}.newThreads(report.Suite{Name: "cts"}, "parent", nil)
+
+		g.Expect(parents.timestamp("C0164BCSY75")).To(gomega.Equal("1594728000.000300"))
+		g.Expect(client.historyCalls).To(gomega.HaveLen(1))
+		g.Expect(client.historyCalls[0].Oldest).To(gomega.BeEmpty())
+	})
+
+	t.Run("uses stored thread without looking into history", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		client := &fakeSlack{}
+		store := fakeThreadStore{"cts": {"C0164BCSY75": "1594728000.000100"}}
+		parents := CLient{client: client, store: store}.newThreads(suite, "parent", nil)
+
+		g.Expect(parents.timestamp("C0164BCSY75")).To(gomega.Equal("1594728000.000100"))
+		g.Expect(client.historyCalls).To(gomega.BeEmpty())
+		g.Expect(client.posted).To(gomega.BeEmpty())
+	})
+
+	t.Run("stores new thread", func(t *testing.T) {
+		g := gomega.NewGomegaWithT(t)
+
+		client := &fakeSlack{}
+		store := fakeThreadStore{}
+		parents := CLient{client: client, store: store}.newThreads(suite, "parent", nil)
+
+		g.Expect(parents.timestamp("C0164BCSY75")).To(gomega.Equal("1594728000.000300"))
+		g.Expect(store).To(gomega.Equal(fakeThreadStore{"cts": {"C0164BCSY75": "1594728000.000300"}}))
+	})
+}
@@ -0,0 +1,176 @@
+// Package spool stores logs in temporary files, so that they don't have to be kept in memory
+// until they're dispatched.
+package spool
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/kyma-project/test-infra/test-log-collector/pkg/report"
+)
+
+// Spool is a temporary directory holding logs of a single report.
+type Spool struct {
+	dir   string
+	limit int64
+}
+
+// New creates temporary directory for logs. Every log is capped at limit bytes, limit <= 0 means no cap.
+func New(limit int64) (*Spool, error) {
+	dir, err := ioutil.TempDir("", "joby-")
+	if err != nil {
+		return nil, errors.Wrap(err, "while creating spool directory")
+	}
+	return &Spool{dir: dir, limit: limit}, nil
+}
+
+// Create returns new file in the spool. It's safe to call it concurrently.
+func (s *Spool) Create() (*File, error) {
+	f, err := ioutil.TempFile(s.dir, "log-")
+	if err != nil {
+		return nil, errors.Wrapf(err, "while creating file in spool directory %s", s.dir)
+	}
+
+	return &File{
+		file:      f,
+		unlimited: s.limit <= 0,
+		headSize:  s.limit / 2,
+		tailSize:  s.limit - s.limit/2,
+	}, nil
+}
+
+// Remove deletes the spool with all logs in it.
+func (s *Spool) Remove() error {
+	return os.RemoveAll(s.dir)
+}
+
+// File is a log written to the spool. If the log exceeds the limit, only its head and tail are kept
+// and the truncation marker is put between them. The head is written straight to the disk,
+// while at most tailSize last bytes are held in memory until the file is closed.
+type File struct {
+	file      *os.File
+	unlimited bool
+	headSize  int64
+	tailSize  int64
+
+	written int64
+	total   int64
+	// tail is a ring buffer, once it's full tailStart is the index of its oldest byte
+	tail      []byte
+	tailStart int
+	// interrupted is the reason why the log stream has been cut short
+	interrupted error
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	f.total += int64(len(p))
+
+	rest := p
+	if f.unlimited || f.written < f.headSize {
+		n := len(p)
+		if !f.unlimited && int64(n) > f.headSize-f.written {
+			n = int(f.headSize - f.written)
+		}
+		written, err := f.file.Write(p[:n])
+		f.written += int64(written)
+		if err != nil {
+			return written, err
+		}
+		rest = p[n:]
+	}
+
+	if len(rest) > 0 {
+		f.appendTail(rest)
+	}
+	return len(p), nil
+}
+
+func (f *File) appendTail(p []byte) {
+	if int64(len(p)) >= f.tailSize {
+		f.tail = append(f.tail[:0], p[int64(len(p))-f.tailSize:]...)
+		f.tailStart = 0
+		return
+	}
+
+	if free := int(f.tailSize) - len(f.tail); free > 0 {
+		n := len(p)
+		if n > free {
+			n = free
+		}
+		f.tail = append(f.tail, p[:n]...)
+		p = p[n:]
+	}
+
+	// the buffer is full, the oldest bytes are overwritten
+	for len(p) > 0 {
+		n := copy(f.tail[f.tailStart:], p)
+		p = p[n:]
+		f.tailStart = (f.tailStart + n) % len(f.tail)
+	}
+}
+
+// writeTail writes the ring buffer from its oldest byte.
+func (f *File) writeTail() error {
+	for _, part := range [][]byte{f.tail[f.tailStart:], f.tail[:f.tailStart]} {
+		n, err := f.file.Write(part)
+		f.written += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Received returns the number of bytes written to the file so far, including the ones which will be truncated.
+func (f *File) Received() int64 {
+	return f.total
+}
+
+// CloseInterrupted is Close for a log whose stream has been cut short, e.g. by a timeout.
+// The reason is written after the tail, so that the log isn't mistaken for a complete one.
+func (f *File) CloseInterrupted(reason error) (report.Log, error) {
+	f.interrupted = reason
+	return f.Close()
+}
+
+// Close writes the buffered tail of the log to the disk and returns reference to the stored log.
+func (f *File) Close() (report.Log, error) {
+	truncated := f.total - f.written - int64(len(f.tail))
+	if truncated > 0 {
+		n, err := fmt.Fprintf(f.file, "\n\n----- joby: %d bytes of logs truncated -----\n\n", truncated)
+		f.written += int64(n)
+		if err != nil {
+			f.file.Close()
+			return report.Log{}, errors.Wrapf(err, "while writing truncation marker to %s", f.file.Name())
+		}
+	}
+
+	err := f.writeTail()
+	f.tail = nil
+	if err != nil {
+		f.file.Close()
+		return report.Log{}, errors.Wrapf(err, "while writing to %s", f.file.Name())
+	}
+
+	if f.interrupted != nil {
+		n, err := fmt.Fprintf(f.file, "\n\n----- joby: log stream interrupted: %s -----\n", f.interrupted)
+		f.written += int64(n)
+		if err != nil {
+			f.file.Close()
+			return report.Log{}, errors.Wrapf(err, "while writing interruption marker to %s", f.file.Name())
+		}
+	}
+
+	if err := f.file.Close(); err != nil {
+		return report.Log{}, errors.Wrapf(err, "while closing %s", f.file.Name())
+	}
+
+	return report.Log{
+		Path:      f.file.Name(),
+		Size:      f.written,
+		Truncated: truncated,
+	}, nil
+}
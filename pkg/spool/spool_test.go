@@ -0,0 +1,166 @@
+package spool
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int64
+		writes        []string
+		wantContent   string
+		wantTruncated int64
+	}{
+		{
+			name:        "keeps whole log without limit",
+			limit:       0,
+			writes:      []string{"line1\n", "line2\n"},
+			wantContent: "line1\nline2\n",
+		},
+		{
+			name:        "keeps whole log within limit",
+			limit:       12,
+			writes:      []string{"line1\n", "line2\n"},
+			wantContent: "line1\nline2\n",
+		},
+		{
+			name:          "keeps head and tail of log exceeding limit",
+			limit:         8,
+			writes:        []string{"aaaa", "bbbb", "cccc", "dddd"},
+			wantContent:   "aaaa\n\n----- joby: 8 bytes of logs truncated -----\n\ndddd",
+			wantTruncated: 8,
+		},
+		{
+			name:          "keeps tail of many small writes after the limit is reached",
+			limit:         8,
+			writes:        append([]string{"aaaa"}, strings.Split("bbbbcccccdefghij", "")...),
+			wantContent:   "aaaa\n\n----- joby: 12 bytes of logs truncated -----\n\nghij",
+			wantTruncated: 12,
+		},
+		{
+			name:          "keeps tail of writes wrapping around the buffer",
+			limit:         8,
+			writes:        []string{"aaaa", "bbb", "ccc", "ddd"},
+			wantContent:   "aaaa\n\n----- joby: 5 bytes of logs truncated -----\n\ncddd",
+			wantTruncated: 5,
+		},
+		{
+			name:          "handles writes bigger than the limit",
+			limit:         4,
+			writes:        []string{"0123456789"},
+			wantContent:   "01\n\n----- joby: 6 bytes of logs truncated -----\n\n89",
+			wantTruncated: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			s, err := New(tt.limit)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			defer func() {
+				g.Expect(s.Remove()).To(gomega.Succeed())
+			}()
+
+			f, err := s.Create()
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+
+			for _, w := range tt.writes {
+				n, err := f.Write([]byte(w))
+				g.Expect(err).ToNot(gomega.HaveOccurred())
+				g.Expect(n).To(gomega.Equal(len(w)))
+			}
+
+			log, err := f.Close()
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(log.Truncated).To(gomega.Equal(tt.wantTruncated))
+			g.Expect(log.Size).To(gomega.Equal(int64(len(tt.wantContent))))
+
+			content, err := ioutil.ReadFile(log.Path)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(string(content)).To(gomega.Equal(tt.wantContent))
+		})
+	}
+}
+
+func TestFile_CloseInterrupted(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	s, err := New(8)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		g.Expect(s.Remove()).To(gomega.Succeed())
+	}()
+
+	f, err := s.Create()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	_, err = f.Write([]byte("aaaabbbbcccc"))
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	log, err := f.CloseInterrupted(errors.New("context deadline exceeded"))
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	content, err := ioutil.ReadFile(log.Path)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	want := "aaaa\n\n----- joby: 4 bytes of logs truncated -----\n\ncccc\n\n----- joby: log stream interrupted: context deadline exceeded -----\n"
+	g.Expect(string(content)).To(gomega.Equal(want))
+	g.Expect(log.Size).To(gomega.Equal(int64(len(want))))
+}
+
+func TestFile_manySmallWrites(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	s, err := New(1024)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		g.Expect(s.Remove()).To(gomega.Succeed())
+	}()
+
+	f, err := s.Create()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	var whole strings.Builder
+	for i := 0; i < 10000; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		whole.WriteString(line)
+		_, err := f.Write([]byte(line))
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+	}
+
+	log, err := f.Close()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	content, err := ioutil.ReadFile(log.Path)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	written := whole.String()
+	g.Expect(string(content)).To(gomega.HavePrefix(written[:512]))
+	g.Expect(string(content)).To(gomega.HaveSuffix(written[len(written)-512:]))
+	g.Expect(log.Truncated).To(gomega.Equal(int64(len(written) - 1024)))
+}
+
+func TestSpool_Remove(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	s, err := New(0)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	f, err := s.Create()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	_, err = f.Write([]byte(strings.Repeat("log", 10)))
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	log, err := f.Close()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	g.Expect(s.Remove()).To(gomega.Succeed())
+
+	_, err = os.Stat(log.Path)
+	g.Expect(os.IsNotExist(err)).To(gomega.BeTrue())
+}